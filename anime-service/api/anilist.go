@@ -2,41 +2,109 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/vrstep/wawatch-backend/models"
 )
 
 const (
+	// AniListURL is the default endpoint, override with ANILIST_URL
 	AniListURL = "https://graphql.anilist.co"
 	// Default timeout for requests in seconds
 	DefaultTimeout = 10
+	// How many times a query is retried after AniList answers 429
+	MaxRateLimitRetries = 3
+	// Give up instead of waiting when AniList asks us to back off for longer than this
+	MaxRetryWait = 2 * time.Minute
 )
 
 type AniListClient struct {
 	httpClient *http.Client
+	endpoint   string
+	limiter    *rateLimiter  // Shared by all goroutines using this client
+	mode       string        // ModeLive, ModeRecord or ModeReplay
+	fixtures   *fixtureStore // Only used in record/replay mode
+	coalescer  *queryCoalescer
+	breaker    *circuitBreaker
 }
 
-// NewAniListClient creates a new client for interacting with AniList API
+// NewAniListClient creates a new client for interacting with AniList API.
+// Configuration comes from the environment:
+//   - ANILIST_URL: GraphQL endpoint (defaults to AniListURL)
+//   - ANILIST_RATE_LIMIT_PER_MINUTE: request budget (defaults to AniList's 90/min)
+//   - ANILIST_MODE: live, record or replay (see fixtures.go)
+//   - ANILIST_FIXTURES_DIR: where record/replay fixtures live (defaults to DefaultFixturesDir)
+//   - ANILIST_BREAKER_THRESHOLD: consecutive failures opening the circuit (defaults to DefaultBreakerThreshold)
+//   - ANILIST_BREAKER_COOLDOWN: how long the circuit stays open before probing, e.g. "1m" (defaults to DefaultBreakerCooldown)
 func NewAniListClient() *AniListClient {
+	endpoint := os.Getenv("ANILIST_URL")
+	if endpoint == "" {
+		endpoint = AniListURL
+	}
+
+	mode := modeFromEnv()
+	fixturesDir := os.Getenv("ANILIST_FIXTURES_DIR")
+	if fixturesDir == "" {
+		fixturesDir = DefaultFixturesDir
+	}
+	if mode != ModeLive {
+		log.Printf("AniList client running in %s mode (fixtures: %s)", mode, fixturesDir)
+	}
+
+	perMinute := DefaultRateLimitPerMinute
+	if v := os.Getenv("ANILIST_RATE_LIMIT_PER_MINUTE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			perMinute = n
+		} else {
+			log.Printf("Warning: Invalid ANILIST_RATE_LIMIT_PER_MINUTE %q, using %d", v, perMinute)
+		}
+	}
+	threshold := DefaultBreakerThreshold
+	if v := os.Getenv("ANILIST_BREAKER_THRESHOLD"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			threshold = n
+		} else {
+			log.Printf("Warning: Invalid ANILIST_BREAKER_THRESHOLD %q, using %d", v, threshold)
+		}
+	}
+	cooldown := DefaultBreakerCooldown
+	if v := os.Getenv("ANILIST_BREAKER_COOLDOWN"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cooldown = d
+		} else {
+			log.Printf("Warning: Invalid ANILIST_BREAKER_COOLDOWN %q, using %s", v, cooldown)
+		}
+	}
 	return &AniListClient{
 		httpClient: &http.Client{
 			Timeout: time.Second * DefaultTimeout,
 		},
+		endpoint:  endpoint,
+		limiter:   newRateLimiter(perMinute),
+		mode:      mode,
+		fixtures:  newFixtureStore(fixturesDir),
+		coalescer: newQueryCoalescer(),
+		breaker:   newCircuitBreaker(threshold, cooldown),
 	}
 }
 
 // GetAnimeByID fetches anime details from AniList by ID
-func (c *AniListClient) GetAnimeByID(id int) (*models.AnimeDetails, error) {
+func (c *AniListClient) GetAnimeByID(ctx context.Context, id int) (*models.AnimeDetails, error) {
 	query := `
     query ($id: Int) {
         Media(id: $id, type: ANIME) {
             id
+            idMal
             title { romaji english native }
+            synonyms
             description
             format
             status
@@ -50,12 +118,22 @@ func (c *AniListClient) GetAnimeByID(id int) (*models.AnimeDetails, error) {
             coverImage { large medium }
             bannerImage
             averageScore
+            meanScore
             popularity
-            studios { nodes { name } }
+            favourites
+            countryOfOrigin
+            source
+            isAdult
+            hashtag
+            trailer { id site thumbnail }
+            tags { id name category rank isGeneralSpoiler isMediaSpoiler isAdult }
+            externalLinks { id url site type language notes isDisabled }
+            studios { nodes { id name isAnimationStudio } }
+            nextAiringEpisode { episode airingAt timeUntilAiring }
         }
     }`
 	variables := map[string]interface{}{"id": id}
-	response, err := c.executeQuery(query, variables)
+	response, err := c.executeQuery(ctx, query, variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch anime by ID %d: %w", id, err)
 	}
@@ -68,62 +146,313 @@ func (c *AniListClient) GetAnimeByID(id int) (*models.AnimeDetails, error) {
 		return nil, fmt.Errorf("failed to parse anime data for ID %d: %w", id, err)
 	}
 	if result.Data.Media == nil {
-		return nil, fmt.Errorf("no anime data returned for ID %d (not found or not ANIME type)", id)
+		return nil, fmt.Errorf("%w: no anime data returned for ID %d (or not ANIME type)", ErrNotFound, id)
 	}
 	return result.Data.Media, nil
 }
 
-// SearchAnime performs a search query on AniList
-func (c *AniListClient) SearchAnime(query string, page int, perPage int) ([]models.AnimeCache, int, error) {
-	gqlQuery := `
-    query ($search: String, $page: Int, $perPage: Int) {
-        Page(page: $page, perPage: $perPage) {
-            pageInfo { total currentPage lastPage hasNextPage }
-            media(search: $search, type: ANIME, sort: POPULARITY_DESC) {
-                id
-                title { romaji english native }
-                coverImage { large medium }
-                format
-                episodes
+// GetAnimeCharacters fetches a page of an anime's characters with their voice actors.
+// language (e.g. "JAPANESE", "ENGLISH") limits the voice actors returned, empty uses AniList's default.
+func (c *AniListClient) GetAnimeCharacters(ctx context.Context, id int, language string, page int, perPage int) ([]models.CharacterEdge, int, error) {
+	query := `
+    query ($id: Int, $page: Int, $perPage: Int, $language: StaffLanguage) {
+        Media(id: $id, type: ANIME) {
+            characters(page: $page, perPage: $perPage, sort: [ROLE, RELEVANCE, ID]) {
+                pageInfo { total }
+                edges {
+                    role
+                    node { id name { full native } image { large medium } }
+                    voiceActorRoles(language: $language, sort: [RELEVANCE, ID]) {
+                        roleNotes
+                        voiceActor { id name { full native } languageV2 image { large medium } }
+                    }
+                }
             }
         }
     }`
-	variables := map[string]interface{}{"search": query, "page": page, "perPage": perPage}
-	return c.executePagedMediaQuery(gqlQuery, variables)
+	variables := map[string]interface{}{"id": id, "page": page, "perPage": perPage}
+	if language != "" {
+		variables["language"] = language
+	}
+	response, err := c.executeQuery(ctx, query, variables)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch characters for anime ID %d: %w", id, err)
+	}
+
+	var result struct {
+		Data struct {
+			Media *struct {
+				Characters struct {
+					PageInfo struct {
+						Total int `json:"total"`
+					} `json:"pageInfo"`
+					Edges []struct {
+						Role            string           `json:"role"`
+						Node            models.Character `json:"node"`
+						VoiceActorRoles []struct {
+							RoleNotes  string `json:"roleNotes"`
+							VoiceActor *struct {
+								models.VoiceActor
+								LanguageV2 string `json:"languageV2"`
+							} `json:"voiceActor"`
+						} `json:"voiceActorRoles"`
+					} `json:"edges"`
+				} `json:"characters"`
+			} `json:"Media"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse characters for anime ID %d: %w", id, err)
+	}
+	if result.Data.Media == nil {
+		return nil, 0, fmt.Errorf("%w: no anime data returned for ID %d (or not ANIME type)", ErrNotFound, id)
+	}
+
+	chars := result.Data.Media.Characters
+	edges := make([]models.CharacterEdge, len(chars.Edges))
+	for i, e := range chars.Edges {
+		edges[i] = models.CharacterEdge{
+			Role:        e.Role,
+			Character:   e.Node,
+			VoiceActors: make(map[string][]models.VoiceActor),
+		}
+		for _, r := range e.VoiceActorRoles {
+			if r.VoiceActor == nil {
+				continue
+			}
+			va := r.VoiceActor.VoiceActor
+			va.Language = r.VoiceActor.LanguageV2
+			va.RoleNotes = r.RoleNotes
+			edges[i].VoiceActors[va.Language] = append(edges[i].VoiceActors[va.Language], va)
+		}
+	}
+	return edges, chars.PageInfo.Total, nil
+}
+
+// SearchAnime performs a search query on AniList (browse preset)
+func (c *AniListClient) SearchAnime(ctx context.Context, query string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.BrowseAnime(ctx, BrowseFilter{Search: query, Sort: "popularity"}, page, perPage)
+}
+
+// MaxPerPage is the largest page AniList serves, larger perPage values are capped to it
+const MaxPerPage = 50
+
+// MaxIDsPerQuery bounds lookups by ID list, which are chunked to the largest page AniList serves
+const MaxIDsPerQuery = MaxPerPage
+
+// ResolveMalIDs looks up the AniList IDs of anime by their MyAnimeList IDs.
+// MAL IDs unknown to AniList are absent from the result.
+func (c *AniListClient) ResolveMalIDs(ctx context.Context, malIDs []int) (map[int]int, error) {
+	gqlQuery := `
+    query ($ids: [Int], $perPage: Int) {
+        Page(page: 1, perPage: $perPage) {
+            media(idMal_in: $ids, type: ANIME) { id idMal }
+        }
+    }`
+	resolved := make(map[int]int, len(malIDs))
+	for start := 0; start < len(malIDs); start += MaxIDsPerQuery {
+		end := start + MaxIDsPerQuery
+		if end > len(malIDs) {
+			end = len(malIDs)
+		}
+		chunk := malIDs[start:end]
+		response, err := c.executeQuery(ctx, gqlQuery, map[string]interface{}{"ids": chunk, "perPage": len(chunk)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve MAL IDs: %w", err)
+		}
+		var result struct {
+			Data struct {
+				Page struct {
+					Media []struct {
+						ID    int  `json:"id"`
+						IDMal *int `json:"idMal"`
+					} `json:"media"`
+				} `json:"Page"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(response, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse MAL ID lookup: %w", err)
+		}
+		for _, m := range result.Data.Page.Media {
+			if m.IDMal != nil {
+				resolved[*m.IDMal] = m.ID
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// GetAnimeByIDs fetches list entries for many anime at once using id_in, in chunks of MaxIDsPerQuery.
+// Results follow the order of ids, IDs unknown to AniList are left out.
+func (c *AniListClient) GetAnimeByIDs(ctx context.Context, ids []int) ([]models.AnimeCache, error) {
+	gqlQuery := `
+    query ($ids: [Int], $perPage: Int) {
+        Page(page: 1, perPage: $perPage) {
+            media(id_in: $ids, type: ANIME) { ` + mediaNodeFields + ` }
+        }
+    }`
+	found := make(map[int]models.AnimeCache, len(ids))
+	for start := 0; start < len(ids); start += MaxIDsPerQuery {
+		end := start + MaxIDsPerQuery
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+		results, _, err := c.executePagedMediaQuery(ctx, gqlQuery, map[string]interface{}{"ids": chunk, "perPage": len(chunk)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch anime batch: %w", err)
+		}
+		for _, anime := range results {
+			found[anime.ID] = anime
+		}
+	}
+
+	animes := make([]models.AnimeCache, 0, len(found))
+	for _, id := range ids {
+		if anime, ok := found[id]; ok {
+			animes = append(animes, anime)
+		}
+	}
+	return animes, nil
+}
+
+// executeQuery handles the execution of GraphQL queries to AniList.
+// Identical queries already in flight are not sent again, callers share the pending response.
+// The shared request is only cancelled once every caller waiting for it has gone (see queryCoalescer).
+// While the circuit breaker is open, queries fail right away with ErrCircuitOpen.
+// Replay mode bypasses the breaker: a fixture miss says nothing about the other fixtures.
+func (c *AniListClient) executeQuery(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
+	key, err := requestHash(query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+	return c.coalescer.Do(ctx, key, func(sharedCtx context.Context) ([]byte, error) {
+		if c.mode == ModeReplay {
+			return c.sendQuery(sharedCtx, query, variables)
+		}
+		if err := c.breaker.Allow(); err != nil {
+			return nil, err
+		}
+		body, err := c.sendQuery(sharedCtx, query, variables)
+		c.breaker.Record(err)
+		return body, err
+	})
 }
 
-// executeQuery handles the execution of GraphQL queries to AniList
-func (c *AniListClient) executeQuery(query string, variables map[string]interface{}) ([]byte, error) {
+// sendQuery sends a GraphQL query to AniList.
+// Requests wait for the shared rate limiter and are retried on 429 using the server's back-off hint.
+func (c *AniListClient) sendQuery(ctx context.Context, query string, variables map[string]interface{}) ([]byte, error) {
 	reqBody, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
-	req, err := http.NewRequest("POST", AniListURL, bytes.NewBuffer(reqBody))
+
+	// Offline development: answer from fixtures without touching the network or the limiter
+	if c.mode == ModeReplay {
+		return c.fixtures.Load(query, variables)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, body, err := c.doRequest(ctx, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		c.limiter.Observe(resp.Header)
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			wait := retryAfter(resp.Header)
+			c.limiter.BlockFor(wait)
+			if attempt >= MaxRateLimitRetries || wait > MaxRetryWait {
+				return nil, &UpstreamError{Source: "anilist API", StatusCode: resp.StatusCode, RetryAfter: wait, Body: string(body)}
+			}
+			log.Printf("AniList rate limit hit, retrying in %s (attempt %d/%d)", wait, attempt+1, MaxRateLimitRetries)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, &UpstreamError{Source: "anilist API", StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		if c.mode == ModeRecord {
+			if err := c.fixtures.Save(query, variables, body); err != nil {
+				log.Printf("Warning: Failed to record AniList fixture: %v", err)
+			}
+		}
+		return body, nil
+	}
+}
+
+// doRequest sends a single GraphQL request and reads the whole response
+func (c *AniListClient) doRequest(ctx context.Context, reqBody []byte) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(reqBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, nil, unreachable("anilist API", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, unreachable("anilist API", err)
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("anilist API returned status %d: %s", resp.StatusCode, string(body))
+	return resp, body, nil
+}
+
+// mediaNode is the short media shape requested by list queries
+type mediaNode struct {
+	ID    int `json:"id"`
+	Title struct {
+		Romaji  string `json:"romaji"`
+		English string `json:"english"`
+		Native  string `json:"native"`
+	} `json:"title"`
+	CoverImage struct {
+		Large  string `json:"large"`
+		Medium string `json:"medium"`
+	} `json:"coverImage"`
+	Format       string   `json:"format"`
+	Episodes     *int     `json:"episodes"`
+	Status       string   `json:"status"`
+	Season       string   `json:"season"`
+	SeasonYear   int      `json:"seasonYear"`
+	AverageScore int      `json:"averageScore"`
+	Popularity   int      `json:"popularity"`
+	Synonyms     []string `json:"synonyms"`
+	IsAdult      bool     `json:"isAdult"`
+}
+
+// mediaNodeFields selects the fields of mediaNode in a GraphQL query
+const mediaNodeFields = `id title { romaji english native } synonyms coverImage { large } format episodes status season seasonYear averageScore popularity isAdult`
+
+// toAnimeCache converts a list entry to a cache entry, preferring the English title
+func (m *mediaNode) toAnimeCache() models.AnimeCache {
+	cache := models.AnimeCache{
+		ID:            m.ID,
+		CoverImage:    m.CoverImage.Large,
+		Format:        m.Format,
+		TotalEpisodes: m.Episodes,
+		Status:        m.Status,
+		Season:        m.Season,
+		SeasonYear:    m.SeasonYear,
+		AverageScore:  m.AverageScore,
+		Popularity:    m.Popularity,
+		IsAdult:       m.IsAdult,
 	}
-	return body, nil
+	cache.SetTitles(m.Title.Romaji, m.Title.English, m.Title.Native, m.Synonyms)
+	return cache
 }
 
 // Helper function to execute paged media queries
-func (c *AniListClient) executePagedMediaQuery(query string, variables map[string]interface{}) ([]models.AnimeCache, int, error) {
-	response, err := c.executeQuery(query, variables)
+func (c *AniListClient) executePagedMediaQuery(ctx context.Context, query string, variables map[string]interface{}) ([]models.AnimeCache, int, error) {
+	response, err := c.executeQuery(ctx, query, variables)
 	if err != nil {
 		return nil, 0, err // Error already has context
 	}
@@ -133,20 +462,7 @@ func (c *AniListClient) executePagedMediaQuery(query string, variables map[strin
 				PageInfo struct {
 					Total int `json:"total"`
 				} `json:"pageInfo"`
-				Media []struct {
-					ID    int `json:"id"`
-					Title struct {
-						Romaji  string `json:"romaji"`
-						English string `json:"english"`
-						Native  string `json:"native"`
-					} `json:"title"`
-					CoverImage struct {
-						Large  string `json:"large"`
-						Medium string `json:"medium"`
-					} `json:"coverImage"`
-					Format   string `json:"format"`
-					Episodes *int   `json:"episodes"`
-				} `json:"media"`
+				Media []mediaNode `json:"media"`
 			} `json:"Page"`
 		} `json:"data"`
 	}
@@ -156,118 +472,41 @@ func (c *AniListClient) executePagedMediaQuery(query string, variables map[strin
 
 	animes := make([]models.AnimeCache, len(result.Data.Page.Media))
 	for i, media := range result.Data.Page.Media {
-		title := media.Title.English
-		if title == "" {
-			title = media.Title.Romaji
-		}
-		if title == "" {
-			title = media.Title.Native
-		}
-		animes[i] = models.AnimeCache{
-			ID:            media.ID,
-			Title:         title,
-			CoverImage:    media.CoverImage.Large,
-			Format:        media.Format,
-			TotalEpisodes: media.Episodes,
-		}
+		animes[i] = media.toAnimeCache()
 	}
 	return animes, result.Data.Page.PageInfo.Total, nil
 }
 
-// GetPopularAnime fetches popular anime
-func (c *AniListClient) GetPopularAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
-	gqlQuery := `
-    query ($page: Int, $perPage: Int) {
-        Page(page: $page, perPage: $perPage) {
-            pageInfo { total }
-            media(type: ANIME, sort: POPULARITY_DESC) {
-                id title { romaji english native } coverImage { large } format episodes
-            }
-        }
-    }`
-	variables := map[string]interface{}{"page": page, "perPage": perPage}
-	return c.executePagedMediaQuery(gqlQuery, variables)
+// GetPopularAnime fetches popular anime (browse preset)
+func (c *AniListClient) GetPopularAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.BrowseAnime(ctx, BrowseFilter{Sort: "popularity"}, page, perPage)
 }
 
-// GetTrendingAnime fetches trending anime
-func (c *AniListClient) GetTrendingAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
-	gqlQuery := `
-    query ($page: Int, $perPage: Int) {
-        Page(page: $page, perPage: $perPage) {
-            pageInfo { total }
-            media(type: ANIME, sort: TRENDING_DESC) {
-                id title { romaji english native } coverImage { large } format episodes
-            }
-        }
-    }`
-	variables := map[string]interface{}{"page": page, "perPage": perPage}
-	return c.executePagedMediaQuery(gqlQuery, variables)
+// GetTrendingAnime fetches trending anime (browse preset)
+func (c *AniListClient) GetTrendingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.BrowseAnime(ctx, BrowseFilter{Sort: "trending"}, page, perPage)
 }
 
-// GetAnimeBySeason fetches anime by year and season
-func (c *AniListClient) GetAnimeBySeason(year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
-	gqlQuery := `
-    query ($page: Int, $perPage: Int, $season: MediaSeason, $seasonYear: Int) {
-        Page(page: $page, perPage: $perPage) {
-            pageInfo { total }
-            media(type: ANIME, season: $season, seasonYear: $seasonYear, sort: POPULARITY_DESC) {
-                id title { romaji english native } coverImage { large } format episodes
-            }
-        }
-    }`
-	variables := map[string]interface{}{"page": page, "perPage": perPage, "season": season, "seasonYear": year}
-	return c.executePagedMediaQuery(gqlQuery, variables)
+// GetAnimeBySeason fetches anime by year and season (browse preset)
+func (c *AniListClient) GetAnimeBySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.BrowseAnime(ctx, BrowseFilter{Season: season, YearFrom: &year, YearTo: &year, Sort: "popularity"}, page, perPage)
 }
 
-// GetUpcomingAnime fetches upcoming anime
-func (c *AniListClient) GetUpcomingAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
-	gqlQuery := `
-    query ($page: Int, $perPage: Int) {
-        Page(page: $page, perPage: $perPage) {
-            pageInfo { total }
-            media(type: ANIME, status: NOT_YET_RELEASED, sort: POPULARITY_DESC) {
-                id title { romaji english native } coverImage { large } format episodes startDate { year month day }
-            }
-        }
-    }`
-	variables := map[string]interface{}{"page": page, "perPage": perPage}
-	return c.executePagedMediaQuery(gqlQuery, variables)
+// GetUpcomingAnime fetches upcoming anime (browse preset)
+func (c *AniListClient) GetUpcomingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.BrowseAnime(ctx, BrowseFilter{Statuses: []string{"NOT_YET_RELEASED"}, Sort: "popularity"}, page, perPage)
 }
 
-// GetRecentlyReleasedAnime fetches recently released or currently airing anime
-func (c *AniListClient) GetRecentlyReleasedAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
-	gqlQuery := `
-    query ($page: Int, $perPage: Int) {
-        Page(page: $page, perPage: $perPage) {
-            pageInfo { total }
-            media(type: ANIME, status_in: [RELEASING, FINISHED], sort: START_DATE_DESC) {
-                id title { romaji english native } coverImage { large } format episodes startDate { year month day }
-            }
-        }
-    }`
-	variables := map[string]interface{}{"page": page, "perPage": perPage}
-	return c.executePagedMediaQuery(gqlQuery, variables)
+// GetRecentlyReleasedAnime fetches recently released or currently airing anime (browse preset)
+func (c *AniListClient) GetRecentlyReleasedAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.BrowseAnime(ctx, BrowseFilter{Statuses: []string{"RELEASING", "FINISHED"}, Sort: "newest"}, page, perPage)
 }
 
-// GetAnimeByTags fetches anime by a list of genres/tags
-func (c *AniListClient) GetAnimeByTags(tags []string, page int, perPage int) ([]models.AnimeCache, int, error) {
+// GetAnimeByTags fetches anime having all the given AniList tags (e.g. "Time Skip") ranked at least minTagRank,
+// and none of excludedTags (browse preset). A nil minTagRank uses DefaultMinTagRank.
+func (c *AniListClient) GetAnimeByTags(ctx context.Context, tags []string, excludedTags []string, minTagRank *int, page int, perPage int) ([]models.AnimeCache, int, error) {
 	if len(tags) == 0 {
-		return []models.AnimeCache{}, 0, fmt.Errorf("no tags provided for GetAnimeByTags")
-	}
-	// AniList's genre_in filter expects a list of strings.
-	gqlQuery := `
-    query ($page: Int, $perPage: Int, $tags: [String]) {
-        Page(page: $page, perPage: $perPage) {
-            pageInfo { total }
-            media(type: ANIME, genre_in: $tags, sort: POPULARITY_DESC) {
-                id title { romaji english native } coverImage { large } format episodes
-            }
-        }
-    }`
-	variables := map[string]interface{}{
-		"page":    page,
-		"perPage": perPage,
-		"tags":    tags,
+		return []models.AnimeCache{}, 0, fmt.Errorf("%w: no tags provided for GetAnimeByTags", ErrInvalidInput)
 	}
-	return c.executePagedMediaQuery(gqlQuery, variables)
+	return c.BrowseAnime(ctx, BrowseFilter{Tags: tags, ExcludedTags: excludedTags, MinTagRank: minTagRank, Sort: "popularity"}, page, perPage)
 }
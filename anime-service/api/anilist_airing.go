@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// GetAiringSchedule fetches a page of episodes airing between from and to (Unix timestamps, inclusive), soonest first.
+// Episodes of adult anime are left out unless ctx allows them, so the page may be shorter than perPage.
+func (c *AniListClient) GetAiringSchedule(ctx context.Context, from int64, to int64, page int, perPage int) ([]models.AiringScheduleEntry, int, error) {
+	query := `
+    query ($from: Int, $to: Int, $page: Int, $perPage: Int) {
+        Page(page: $page, perPage: $perPage) {
+            pageInfo { total }
+            airingSchedules(airingAt_greater: $from, airingAt_lesser: $to, sort: TIME) {
+                id episode airingAt
+                media { ` + mediaNodeFields + ` }
+            }
+        }
+    }`
+	// AniList's bounds are exclusive
+	variables := map[string]interface{}{"from": from - 1, "to": to + 1, "page": page, "perPage": perPage}
+	response, err := c.executeQuery(ctx, query, variables)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch airing schedule: %w", err)
+	}
+
+	var result struct {
+		Data struct {
+			Page struct {
+				PageInfo struct {
+					Total int `json:"total"`
+				} `json:"pageInfo"`
+				AiringSchedules []struct {
+					ID       int       `json:"id"`
+					Episode  int       `json:"episode"`
+					AiringAt int64     `json:"airingAt"`
+					Media    mediaNode `json:"media"`
+				} `json:"airingSchedules"`
+			} `json:"Page"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse airing schedule: %w", err)
+	}
+
+	schedule := make([]models.AiringScheduleEntry, 0, len(result.Data.Page.AiringSchedules))
+	for _, s := range result.Data.Page.AiringSchedules {
+		if s.Media.IsAdult && !AdultContentAllowed(ctx) {
+			continue
+		}
+		schedule = append(schedule, models.AiringScheduleEntry{ID: s.ID, Episode: s.Episode, AiringAt: s.AiringAt, Anime: s.Media.toAnimeCache()})
+	}
+	return schedule, result.Data.Page.PageInfo.Total, nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// GetGenres fetches the list of genres AniList knows
+func (c *AniListClient) GetGenres(ctx context.Context) ([]string, error) {
+	response, err := c.executeQuery(ctx, `query { GenreCollection }`, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genre collection: %w", err)
+	}
+	var result struct {
+		Data struct {
+			GenreCollection []string `json:"GenreCollection"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse genre collection: %w", err)
+	}
+	return result.Data.GenreCollection, nil
+}
+
+// GetTags fetches AniList's whole tag catalog, including spoiler and adult tags
+func (c *AniListClient) GetTags(ctx context.Context) ([]models.MediaTag, error) {
+	query := `
+    query {
+        MediaTagCollection { id name description category isGeneralSpoiler isAdult }
+    }`
+	response, err := c.executeQuery(ctx, query, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tag collection: %w", err)
+	}
+	var result struct {
+		Data struct {
+			MediaTagCollection []models.MediaTag `json:"MediaTagCollection"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse tag collection: %w", err)
+	}
+	return result.Data.MediaTagCollection, nil
+}
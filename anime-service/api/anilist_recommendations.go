@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// GetAnimeRecommendations fetches a page of the anime AniList users recommend to fans of an anime, best rated first.
+// Adult anime are left out unless ctx allows them, so the page may be shorter than perPage.
+func (c *AniListClient) GetAnimeRecommendations(ctx context.Context, id int, page int, perPage int) ([]models.Recommendation, int, error) {
+	query := `
+    query ($id: Int, $page: Int, $perPage: Int) {
+        Media(id: $id, type: ANIME) {
+            recommendations(page: $page, perPage: $perPage, sort: [RATING_DESC, ID]) {
+                pageInfo { total }
+                nodes { rating mediaRecommendation { ` + mediaNodeFields + ` } }
+            }
+        }
+    }`
+	variables := map[string]interface{}{"id": id, "page": page, "perPage": perPage}
+	response, err := c.executeQuery(ctx, query, variables)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch recommendations for anime ID %d: %w", id, err)
+	}
+
+	var result struct {
+		Data struct {
+			Media *struct {
+				Recommendations struct {
+					PageInfo struct {
+						Total int `json:"total"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						Rating              int        `json:"rating"`
+						MediaRecommendation *mediaNode `json:"mediaRecommendation"`
+					} `json:"nodes"`
+				} `json:"recommendations"`
+			} `json:"Media"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse recommendations for anime ID %d: %w", id, err)
+	}
+	if result.Data.Media == nil {
+		return nil, 0, fmt.Errorf("%w: no anime data returned for ID %d (or not ANIME type)", ErrNotFound, id)
+	}
+
+	recommendations := []models.Recommendation{}
+	for _, node := range result.Data.Media.Recommendations.Nodes {
+		// Recommended media that was since removed from AniList comes back null
+		if node.MediaRecommendation == nil || (node.MediaRecommendation.IsAdult && !AdultContentAllowed(ctx)) {
+			continue
+		}
+		recommendations = append(recommendations, models.Recommendation{
+			Anime:  node.MediaRecommendation.toAnimeCache(),
+			Rating: node.Rating,
+		})
+	}
+	return recommendations, result.Data.Media.Recommendations.PageInfo.Total, nil
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// relatedMediaFields selects the fields of models.RelatedMedia in a GraphQL query
+const relatedMediaFields = `id type title { romaji english native } format status episodes coverImage { large medium } startDate { year month day }`
+
+// GetAnimeRelations fetches an anime's summary and its direct relations (sequels, prequels, side stories, adaptations...)
+func (c *AniListClient) GetAnimeRelations(ctx context.Context, id int) (*models.RelatedMedia, []models.RelationEdge, error) {
+	query := `
+    query ($id: Int) {
+        Media(id: $id, type: ANIME) {
+            ` + relatedMediaFields + `
+            relations {
+                edges { relationType(version: 2) node { ` + relatedMediaFields + ` } }
+            }
+        }
+    }`
+	response, err := c.executeQuery(ctx, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch relations for anime ID %d: %w", id, err)
+	}
+
+	var result struct {
+		Data struct {
+			Media *struct {
+				models.RelatedMedia
+				Relations struct {
+					Edges []models.RelationEdge `json:"edges"`
+				} `json:"relations"`
+			} `json:"Media"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse relations for anime ID %d: %w", id, err)
+	}
+	if result.Data.Media == nil {
+		return nil, nil, fmt.Errorf("%w: no anime data returned for ID %d (or not ANIME type)", ErrNotFound, id)
+	}
+	edges := result.Data.Media.Relations.Edges
+	if edges == nil {
+		edges = []models.RelationEdge{}
+	}
+	return &result.Data.Media.RelatedMedia, edges, nil
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// Filmography kinds for GetStaff
+const (
+	StaffWorksProduction = "staff" // Anime the person worked on as staff (director, composer, ...)
+	StaffWorksVoice      = "voice" // Anime the person voiced characters in
+)
+
+// GetStudio fetches a studio profile and a page of its anime, newest first
+func (c *AniListClient) GetStudio(ctx context.Context, id int, page int, perPage int) (*models.Studio, []models.AnimeWork, int, error) {
+	query := `
+    query ($id: Int, $page: Int, $perPage: Int) {
+        Studio(id: $id) {
+            id name isAnimationStudio siteUrl favourites
+            media(page: $page, perPage: $perPage, sort: START_DATE_DESC) {
+                pageInfo { total }
+                edges { isMainStudio node { ` + mediaNodeFields + ` type } }
+            }
+        }
+    }`
+	response, err := c.executeQuery(ctx, query, map[string]interface{}{"id": id, "page": page, "perPage": perPage})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to fetch studio ID %d: %w", id, err)
+	}
+
+	var result struct {
+		Data struct {
+			Studio *struct {
+				models.Studio
+				Media struct {
+					PageInfo struct {
+						Total int `json:"total"`
+					} `json:"pageInfo"`
+					Edges []struct {
+						IsMainStudio bool `json:"isMainStudio"`
+						Node         struct {
+							mediaNode
+							Type string `json:"type"`
+						} `json:"node"`
+					} `json:"edges"`
+				} `json:"media"`
+			} `json:"Studio"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to parse studio ID %d: %w", id, err)
+	}
+	if result.Data.Studio == nil {
+		return nil, nil, 0, fmt.Errorf("%w: no studio data returned for ID %d", ErrNotFound, id)
+	}
+
+	studio := result.Data.Studio
+	works := make([]models.AnimeWork, 0, len(studio.Media.Edges))
+	for _, e := range studio.Media.Edges {
+		if e.Node.Type != "ANIME" { // Studios can be credited on manga adaptations too
+			continue
+		}
+		works = append(works, models.AnimeWork{Anime: e.Node.toAnimeCache(), IsMain: e.IsMainStudio})
+	}
+	return &studio.Studio, works, studio.Media.PageInfo.Total, nil
+}
+
+// GetStaff fetches a staff profile and a page of their anime, newest first.
+// works is StaffWorksProduction (staff credits) or StaffWorksVoice (voice acting roles).
+func (c *AniListClient) GetStaff(ctx context.Context, id int, works string, page int, perPage int) (*models.Staff, []models.AnimeWork, int, error) {
+	connection := `staffMedia(type: ANIME, page: $page, perPage: $perPage, sort: START_DATE_DESC) {
+                pageInfo { total }
+                edges { staffRole node { ` + mediaNodeFields + ` } }
+            }`
+	if works == StaffWorksVoice {
+		connection = `characterMedia(page: $page, perPage: $perPage, sort: START_DATE_DESC) {
+                pageInfo { total }
+                edges { characterRole characters { id name { full native } image { large medium } } node { ` + mediaNodeFields + ` type } }
+            }`
+	}
+	query := `
+    query ($id: Int, $page: Int, $perPage: Int) {
+        Staff(id: $id) {
+            id name { full native } languageV2 image { large medium } description
+            primaryOccupations gender dateOfBirth { year month day } homeTown yearsActive siteUrl favourites
+            ` + connection + `
+        }
+    }`
+	response, err := c.executeQuery(ctx, query, map[string]interface{}{"id": id, "page": page, "perPage": perPage})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to fetch staff ID %d: %w", id, err)
+	}
+
+	type staffEdges struct {
+		PageInfo struct {
+			Total int `json:"total"`
+		} `json:"pageInfo"`
+		Edges []struct {
+			StaffRole     string             `json:"staffRole"`
+			CharacterRole string             `json:"characterRole"`
+			Characters    []models.Character `json:"characters"`
+			Node          struct {
+				mediaNode
+				Type string `json:"type"`
+			} `json:"node"`
+		} `json:"edges"`
+	}
+	var result struct {
+		Data struct {
+			Staff *struct {
+				models.Staff
+				LanguageV2     string     `json:"languageV2"`
+				StaffMedia     staffEdges `json:"staffMedia"`
+				CharacterMedia staffEdges `json:"characterMedia"`
+			} `json:"Staff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to parse staff ID %d: %w", id, err)
+	}
+	if result.Data.Staff == nil {
+		return nil, nil, 0, fmt.Errorf("%w: no staff data returned for ID %d", ErrNotFound, id)
+	}
+
+	staff := result.Data.Staff
+	staff.Staff.Language = staff.LanguageV2
+	edges := staff.StaffMedia
+	if works == StaffWorksVoice {
+		edges = staff.CharacterMedia
+	}
+
+	// AniList returns one edge per role, merge them so each anime appears once
+	var filmography []models.AnimeWork
+	index := make(map[int]int)
+	for _, e := range edges.Edges {
+		if works == StaffWorksVoice && e.Node.Type != "ANIME" {
+			continue
+		}
+		i, seen := index[e.Node.ID]
+		if !seen {
+			i = len(filmography)
+			index[e.Node.ID] = i
+			filmography = append(filmography, models.AnimeWork{Anime: e.Node.toAnimeCache()})
+		}
+		work := &filmography[i]
+		if role := e.StaffRole + e.CharacterRole; role != "" {
+			work.Roles = append(work.Roles, role)
+		}
+		work.Characters = append(work.Characters, e.Characters...)
+	}
+	return &staff.Staff, filmography, edges.PageInfo.Total, nil
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBreakerThreshold is how many consecutive upstream failures open the circuit
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerCooldown is how long an open circuit rejects queries before letting a probe through
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling AniList while the circuit breaker is open
+var ErrCircuitOpen = fmt.Errorf("anilist circuit breaker is open: %w", ErrUpstreamUnavailable)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"    // Queries go through
+	CircuitOpen     = "open"      // Queries fail fast with ErrCircuitOpen
+	CircuitHalfOpen = "half-open" // One probe query at a time decides whether to close or re-open
+)
+
+// CircuitStatus is a snapshot of the circuit breaker
+type CircuitStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	Trips               int64      `json:"trips"` // Times the circuit opened since startup
+}
+
+// CircuitReporter is implemented by clients guarded by a circuit breaker
+type CircuitReporter interface {
+	Circuit() CircuitStatus
+}
+
+var _ CircuitReporter = (*AniListClient)(nil)
+
+// circuitBreaker stops sending queries to AniList after repeated failures, so requests fail fast
+// (and callers can fall back on local data) instead of each waiting for a timeout.
+// Once the cooldown has passed, the next query is let through as a probe: success closes the circuit,
+// failure re-opens it for another cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool // A half-open probe is in flight
+	trips    int64
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, state: CircuitClosed}
+}
+
+// Allow returns ErrCircuitOpen when a query must not be sent.
+// Every allowed query must be followed by a call to Record.
+func (b *circuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		log.Printf("AniList circuit breaker half-open, probing")
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record updates the breaker with the outcome of an allowed query.
+// Only failures to get an answer count: AniList saying "not found" or "bad request" proves it is up,
+// and cancelled queries tell nothing either way.
+func (b *circuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasProbe := b.state == CircuitHalfOpen && b.probing
+	if wasProbe {
+		b.probing = false
+	}
+	switch {
+	case err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalidInput):
+		if b.state != CircuitClosed {
+			log.Printf("AniList circuit breaker closed, AniList is answering again")
+		}
+		b.state = CircuitClosed
+		b.failures = 0
+	case errors.Is(err, context.Canceled) || errors.Is(err, ErrRateLimited) || !errors.Is(err, ErrUpstreamUnavailable):
+		// Neutral: the rate limiter deals with 429s, cancellations come from our side
+	default:
+		b.failures++
+		if wasProbe || (b.state == CircuitClosed && b.failures >= b.threshold) {
+			b.state = CircuitOpen
+			b.openedAt = time.Now()
+			b.trips++
+			log.Printf("Warning: AniList circuit breaker opened after %d consecutive failures, probing again in %s: %v", b.failures, b.cooldown, err)
+		}
+	}
+}
+
+// Status returns a snapshot of the breaker
+func (b *circuitBreaker) Status() CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := CircuitStatus{State: b.state, ConsecutiveFailures: b.failures, Trips: b.trips}
+	if b.state != CircuitClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// Circuit reports the state of the AniList circuit breaker
+func (c *AniListClient) Circuit() CircuitStatus {
+	return c.breaker.Status()
+}
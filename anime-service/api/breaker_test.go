@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var errUpstream = &UpstreamError{Source: SourceAniList, StatusCode: http.StatusBadGateway}
+
+func TestCircuitBreakerRecord(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error
+		wantState    string
+		wantFailures int
+		wantTrips    int64
+	}{
+		{name: "below the threshold", errs: []error{errUpstream, errUpstream}, wantState: CircuitClosed, wantFailures: 2},
+		{name: "threshold opens", errs: []error{errUpstream, errUpstream, errUpstream}, wantState: CircuitOpen, wantFailures: 3, wantTrips: 1},
+		{name: "success resets", errs: []error{errUpstream, errUpstream, nil, errUpstream}, wantState: CircuitClosed, wantFailures: 1},
+		{name: "not found proves AniList is up", errs: []error{errUpstream, errUpstream, &UpstreamError{Source: SourceAniList, StatusCode: http.StatusNotFound}}, wantState: CircuitClosed},
+		{name: "invalid input proves AniList is up", errs: []error{errUpstream, errUpstream, ErrInvalidInput}, wantState: CircuitClosed},
+		{name: "cancel is neutral", errs: []error{errUpstream, errUpstream, context.Canceled, context.Canceled}, wantState: CircuitClosed, wantFailures: 2},
+		{name: "cancel keeps the count", errs: []error{errUpstream, errUpstream, context.Canceled, errUpstream}, wantState: CircuitOpen, wantFailures: 3, wantTrips: 1},
+		{name: "429 is neutral", errs: []error{errUpstream, ErrRateLimited, ErrRateLimited, errUpstream}, wantState: CircuitClosed, wantFailures: 2},
+		{name: "429 response is neutral", errs: []error{errUpstream, errUpstream, &UpstreamError{Source: SourceAniList, StatusCode: http.StatusTooManyRequests}}, wantState: CircuitClosed, wantFailures: 2},
+		{name: "other errors are neutral", errs: []error{errors.New("bad json"), errors.New("bad json"), errors.New("bad json")}, wantState: CircuitClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreaker(3, time.Hour)
+			for i, err := range tt.errs {
+				if allowErr := b.Allow(); allowErr != nil {
+					t.Fatalf("Allow before query %d = %v, want nil", i, allowErr)
+				}
+				b.Record(err)
+			}
+			status := b.Status()
+			if status.State != tt.wantState || status.ConsecutiveFailures != tt.wantFailures || status.Trips != tt.wantTrips {
+				t.Errorf("Status = %s with %d failures and %d trips, want %s with %d failures and %d trips",
+					status.State, status.ConsecutiveFailures, status.Trips, tt.wantState, tt.wantFailures, tt.wantTrips)
+			}
+			if (status.OpenedAt != nil) != (tt.wantState != CircuitClosed) {
+				t.Errorf("OpenedAt = %v in state %s", status.OpenedAt, status.State)
+			}
+			if allowErr := b.Allow(); (allowErr != nil) != (tt.wantState == CircuitOpen) {
+				t.Errorf("Allow in state %s = %v", tt.wantState, allowErr)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		probeErr  error
+		wantState string
+		wantTrips int64
+		wantAllow bool // Whether the next query goes through right after the probe
+	}{
+		{name: "probe success closes", probeErr: nil, wantState: CircuitClosed, wantTrips: 1, wantAllow: true},
+		{name: "probe not found closes", probeErr: ErrNotFound, wantState: CircuitClosed, wantTrips: 1, wantAllow: true},
+		{name: "probe failure re-opens", probeErr: errUpstream, wantState: CircuitOpen, wantTrips: 2},
+		{name: "cancelled probe lets another probe through", probeErr: context.Canceled, wantState: CircuitHalfOpen, wantTrips: 1, wantAllow: true},
+		{name: "rate limited probe lets another probe through", probeErr: ErrRateLimited, wantState: CircuitHalfOpen, wantTrips: 1, wantAllow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const cooldown = 10 * time.Millisecond
+			b := newCircuitBreaker(1, cooldown)
+			if err := b.Allow(); err != nil {
+				t.Fatalf("Allow on a closed circuit = %v", err)
+			}
+			b.Record(errUpstream)
+			if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("Allow during the cooldown = %v, want ErrCircuitOpen", err)
+			}
+			if err := b.Allow(); !errors.Is(err, ErrUpstreamUnavailable) {
+				t.Fatalf("ErrCircuitOpen must wrap ErrUpstreamUnavailable, got %v", err)
+			}
+
+			time.Sleep(cooldown + 5*time.Millisecond)
+			if err := b.Allow(); err != nil {
+				t.Fatalf("Allow after the cooldown = %v, want the probe let through", err)
+			}
+			if state := b.Status().State; state != CircuitHalfOpen {
+				t.Fatalf("state while probing = %s, want %s", state, CircuitHalfOpen)
+			}
+			if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("second Allow while probing = %v, want ErrCircuitOpen", err)
+			}
+
+			b.Record(tt.probeErr)
+			status := b.Status()
+			if status.State != tt.wantState || status.Trips != tt.wantTrips {
+				t.Errorf("Status after the probe = %s with %d trips, want %s with %d trips", status.State, status.Trips, tt.wantState, tt.wantTrips)
+			}
+			if err := b.Allow(); (err == nil) != tt.wantAllow {
+				t.Errorf("Allow after the probe = %v, want allowed %t", err, tt.wantAllow)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// Sort keys accepted by BrowseFilter.Sort, mapped to AniList MediaSort values
+var browseSorts = map[string][]string{
+	"popularity": {"POPULARITY_DESC"},
+	"trending":   {"TRENDING_DESC", "POPULARITY_DESC"},
+	"score":      {"SCORE_DESC"},
+	"favourites": {"FAVOURITES_DESC"},
+	"newest":     {"START_DATE_DESC"},
+	"oldest":     {"START_DATE"},
+	"title":      {"TITLE_ROMAJI"},
+	"episodes":   {"EPISODES_DESC"},
+	"relevance":  {"SEARCH_MATCH", "POPULARITY_DESC"}, // Only meaningful with Search
+}
+
+// DefaultBrowseSort is used when BrowseFilter.Sort is empty
+const DefaultBrowseSort = "popularity"
+
+var (
+	validFormats  = []string{"TV", "TV_SHORT", "MOVIE", "SPECIAL", "OVA", "ONA", "MUSIC"}
+	validStatuses = []string{"FINISHED", "RELEASING", "NOT_YET_RELEASED", "CANCELLED", "HIATUS"}
+	validSeasons  = []string{"WINTER", "SPRING", "SUMMER", "FALL"}
+)
+
+// Bounds checked by BrowseFilter.Validate
+const (
+	MaxBrowseListValues = 25 // Per list filter (genres, tags, formats...)
+	MinBrowseYear       = 1900
+	MaxBrowseYear       = 2100
+	MaxBrowseScore      = 100
+	// DefaultMinTagRank ignores tags voted as only loosely relevant (AniList ranks tags 0-100 per anime)
+	DefaultMinTagRank = 60
+)
+
+// BrowseFilter combines every filter supported by BrowseAnime. Zero values mean "no filter",
+// except for IncludeAdult: adult anime are left out unless it is set. Range bounds are inclusive.
+type BrowseFilter struct {
+	Search         string
+	Genres         []string // All must match
+	ExcludedGenres []string
+	Tags           []string // All must match, with at least MinTagRank
+	ExcludedTags   []string
+	MinTagRank     *int     // Tag relevance threshold for Tags, 0-100, DefaultMinTagRank if nil
+	Formats        []string // Any may match (TV, MOVIE, ...)
+	Statuses       []string // Any may match (RELEASING, FINISHED, ...)
+	Season         string   // WINTER, SPRING, SUMMER or FALL
+	YearFrom       *int
+	YearTo         *int
+	ScoreMin       *int // Average score, 0-100
+	ScoreMax       *int
+	EpisodesMin    *int
+	EpisodesMax    *int
+	Sort           string // One of the browseSorts keys, DefaultBrowseSort if empty
+	IncludeAdult   bool   // Also set by BrowseAnime when the context allows adult content (see WithAdultContent)
+}
+
+// Validate normalizes enum values to AniList's casing and checks every filter,
+// returning a message suitable for API clients on the first problem found
+func (f *BrowseFilter) Validate() error {
+	lists := []struct {
+		name   string
+		values []string
+	}{
+		{"genres", f.Genres}, {"excludeGenres", f.ExcludedGenres}, {"tags", f.Tags}, {"excludeTags", f.ExcludedTags},
+		{"formats", f.Formats}, {"status", f.Statuses},
+	}
+	for _, l := range lists {
+		if len(l.values) > MaxBrowseListValues {
+			return fmt.Errorf("%s accepts at most %d values", l.name, MaxBrowseListValues)
+		}
+	}
+
+	var err error
+	if f.Formats, err = normalizeEnums("formats", f.Formats, validFormats); err != nil {
+		return err
+	}
+	if f.Statuses, err = normalizeEnums("status", f.Statuses, validStatuses); err != nil {
+		return err
+	}
+	if f.Season != "" {
+		seasons, err := normalizeEnums("season", []string{f.Season}, validSeasons)
+		if err != nil {
+			return err
+		}
+		f.Season = seasons[0]
+	}
+
+	if err := checkRange("year", f.YearFrom, f.YearTo, MinBrowseYear, MaxBrowseYear); err != nil {
+		return err
+	}
+	if err := checkRange("minTagRank", f.MinTagRank, nil, 0, MaxBrowseScore); err != nil {
+		return err
+	}
+	if err := checkRange("score", f.ScoreMin, f.ScoreMax, 0, MaxBrowseScore); err != nil {
+		return err
+	}
+	if err := checkRange("episodes", f.EpisodesMin, f.EpisodesMax, 0, -1); err != nil {
+		return err
+	}
+
+	f.Sort = strings.ToLower(strings.TrimSpace(f.Sort))
+	if f.Sort == "" {
+		f.Sort = DefaultBrowseSort
+	}
+	if _, ok := browseSorts[f.Sort]; !ok {
+		return fmt.Errorf("unknown sort %q (use popularity, trending, score, favourites, newest, oldest, title, episodes or relevance)", f.Sort)
+	}
+	if f.Sort == "relevance" && f.Search == "" {
+		return fmt.Errorf("sort relevance requires a search text")
+	}
+	return nil
+}
+
+// normalizeEnums upper-cases values and checks them against the allowed AniList enum values
+func normalizeEnums(name string, values []string, allowed []string) ([]string, error) {
+	normalized := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ToUpper(strings.TrimSpace(v))
+		found := false
+		for _, a := range allowed {
+			if v == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid %s value %q (use %s)", name, v, strings.Join(allowed, ", "))
+		}
+		normalized = append(normalized, v)
+	}
+	return normalized, nil
+}
+
+// checkRange validates an inclusive range, max < 0 meaning unbounded
+func checkRange(name string, from, to *int, min, max int) error {
+	for _, v := range []*int{from, to} {
+		if v == nil {
+			continue
+		}
+		if *v < min || (max >= 0 && *v > max) {
+			if max < 0 {
+				return fmt.Errorf("%s must be at least %d", name, min)
+			}
+			return fmt.Errorf("%s must be between %d and %d", name, min, max)
+		}
+	}
+	if from != nil && to != nil && *from > *to {
+		return fmt.Errorf("%s range is empty (min %d is above max %d)", name, *from, *to)
+	}
+	return nil
+}
+
+// browseQuery collects the arguments and variables of a dynamically built media query
+type browseQuery struct {
+	declarations []string
+	arguments    []string
+	variables    map[string]interface{}
+}
+
+// add declares a GraphQL variable and passes it as the given media argument
+func (q *browseQuery) add(argument string, variable string, gqlType string, value interface{}) {
+	q.declarations = append(q.declarations, fmt.Sprintf("$%s: %s", variable, gqlType))
+	q.arguments = append(q.arguments, fmt.Sprintf("%s: $%s", argument, variable))
+	q.variables[variable] = value
+}
+
+// buildBrowseQuery turns a validated filter into a paged media query.
+// Only the filters that are set appear in the query, AniList's *_greater/*_lesser arguments are exclusive.
+func buildBrowseQuery(f BrowseFilter, page int, perPage int) (string, map[string]interface{}) {
+	q := &browseQuery{variables: map[string]interface{}{"page": page, "perPage": perPage}}
+	q.declarations = []string{"$page: Int", "$perPage: Int"}
+	q.arguments = []string{"type: ANIME"}
+
+	if f.Search != "" {
+		q.add("search", "search", "String", f.Search)
+	}
+	if len(f.Genres) > 0 {
+		q.add("genre_in", "genres", "[String]", f.Genres)
+	}
+	if len(f.ExcludedGenres) > 0 {
+		q.add("genre_not_in", "excludedGenres", "[String]", f.ExcludedGenres)
+	}
+	if len(f.Tags) > 0 {
+		q.add("tag_in", "tags", "[String]", f.Tags)
+		minRank := DefaultMinTagRank
+		if f.MinTagRank != nil {
+			minRank = *f.MinTagRank
+		}
+		q.add("minimumTagRank", "minimumTagRank", "Int", minRank)
+	}
+	if len(f.ExcludedTags) > 0 {
+		q.add("tag_not_in", "excludedTags", "[String]", f.ExcludedTags)
+	}
+	if len(f.Formats) > 0 {
+		q.add("format_in", "formats", "[MediaFormat]", f.Formats)
+	}
+	if len(f.Statuses) > 0 {
+		q.add("status_in", "statuses", "[MediaStatus]", f.Statuses)
+	}
+	if f.Season != "" {
+		q.add("season", "season", "MediaSeason", f.Season)
+	}
+	if f.Season != "" && f.YearFrom != nil && f.YearTo != nil && *f.YearFrom == *f.YearTo {
+		// A season belongs to one season year, which may differ from the start date's (e.g. late December premieres)
+		q.add("seasonYear", "seasonYear", "Int", *f.YearFrom)
+	} else {
+		// Start dates are FuzzyDateInt (YYYYMMDD, with 00 for unknown parts)
+		if f.YearFrom != nil {
+			q.add("startDate_greater", "startDateGreater", "FuzzyDateInt", *f.YearFrom*10000-1)
+		}
+		if f.YearTo != nil {
+			q.add("startDate_lesser", "startDateLesser", "FuzzyDateInt", (*f.YearTo+1)*10000)
+		}
+	}
+	if f.ScoreMin != nil {
+		q.add("averageScore_greater", "scoreGreater", "Int", *f.ScoreMin-1)
+	}
+	if f.ScoreMax != nil {
+		q.add("averageScore_lesser", "scoreLesser", "Int", *f.ScoreMax+1)
+	}
+	if f.EpisodesMin != nil {
+		q.add("episodes_greater", "episodesGreater", "Int", *f.EpisodesMin-1)
+	}
+	if f.EpisodesMax != nil {
+		q.add("episodes_lesser", "episodesLesser", "Int", *f.EpisodesMax+1)
+	}
+	if !f.IncludeAdult {
+		q.add("isAdult", "isAdult", "Boolean", false)
+	}
+
+	sort := f.Sort
+	if sort == "" {
+		sort = DefaultBrowseSort
+	}
+	q.arguments = append(q.arguments, "sort: ["+strings.Join(browseSorts[sort], ", ")+"]")
+
+	query := `
+    query (` + strings.Join(q.declarations, ", ") + `) {
+        Page(page: $page, perPage: $perPage) {
+            pageInfo { total }
+            media(` + strings.Join(q.arguments, ", ") + `) {
+                ` + mediaNodeFields + `
+            }
+        }
+    }`
+	return query, q.variables
+}
+
+// BrowseAnime fetches a page of anime matching any combination of filters.
+// Adult anime are only included when ctx allows them (see WithAdultContent).
+func (c *AniListClient) BrowseAnime(ctx context.Context, filter BrowseFilter, page int, perPage int) ([]models.AnimeCache, int, error) {
+	filter.IncludeAdult = filter.IncludeAdult || AdultContentAllowed(ctx)
+	if err := filter.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("%w: invalid browse filter: %w", ErrInvalidInput, err)
+	}
+	query, variables := buildBrowseQuery(filter, page, perPage)
+	results, total, err := c.executePagedMediaQuery(ctx, query, variables)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to browse anime: %w", err)
+	}
+	return results, total, nil
+}
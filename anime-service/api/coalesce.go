@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// QueryStats counts executeQuery calls and how many of them shared another caller's in-flight request
+type QueryStats struct {
+	Requests      int64 `json:"requests"`      // Queries asked for by callers
+	UpstreamCalls int64 `json:"upstreamCalls"` // Queries actually sent to AniList (or the fixtures)
+	Coalesced     int64 `json:"coalesced"`     // Requests answered by an identical in-flight query
+}
+
+// StatsReporter is implemented by clients that track query coalescing
+type StatsReporter interface {
+	Stats() QueryStats
+}
+
+var _ StatsReporter = (*AniListClient)(nil)
+
+// inflightQuery is an upstream query shared by every caller asking for it while it runs
+type inflightQuery struct {
+	done    chan struct{} // Closed once body and err are set
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// queryCoalescer merges identical concurrent queries (same query text and variables) into one upstream call.
+// The upstream call is detached from the first caller's context: it keeps running while anyone still waits
+// for it and is cancelled as soon as the last waiter gives up.
+type queryCoalescer struct {
+	mu       sync.Mutex
+	inflight map[string]*inflightQuery
+
+	requests      atomic.Int64
+	upstreamCalls atomic.Int64
+}
+
+func newQueryCoalescer() *queryCoalescer {
+	return &queryCoalescer{inflight: make(map[string]*inflightQuery)}
+}
+
+// Do runs fn once for all concurrent callers with the same key and hands each of them the result.
+// The response body is shared between callers and must not be modified.
+func (q *queryCoalescer) Do(ctx context.Context, key string, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	q.requests.Add(1)
+
+	q.mu.Lock()
+	call, ok := q.inflight[key]
+	if !ok {
+		sharedCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &inflightQuery{done: make(chan struct{}), cancel: cancel}
+		q.inflight[key] = call
+		q.upstreamCalls.Add(1)
+		go func() {
+			call.body, call.err = fn(sharedCtx)
+			q.forget(key, call)
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	q.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, call.err
+	case <-ctx.Done():
+		q.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Nobody needs the result anymore, later callers start a fresh query
+			call.cancel()
+			if q.inflight[key] == call {
+				delete(q.inflight, key)
+			}
+		}
+		q.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes a finished call, unless it was already replaced by a newer one
+func (q *queryCoalescer) forget(key string, call *inflightQuery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.inflight[key] == call {
+		delete(q.inflight, key)
+	}
+}
+
+// Stats returns the coalescing counters since the client was created
+func (c *AniListClient) Stats() QueryStats {
+	requests := c.coalescer.requests.Load()
+	upstream := c.coalescer.upstreamCalls.Load()
+	return QueryStats{Requests: requests, UpstreamCalls: upstream, Coalesced: requests - upstream}
+}
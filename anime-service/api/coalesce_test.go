@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until the in-flight query for key has n waiters
+func waitForWaiters(t *testing.T, q *queryCoalescer, key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		q.mu.Lock()
+		call, ok := q.inflight[key]
+		waiters := 0
+		if ok {
+			waiters = call.waiters
+		}
+		q.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("query %q never reached %d waiters", key, n)
+}
+
+func TestQueryCoalescerSharesResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		callers int
+		body    []byte
+		err     error
+	}{
+		{name: "single caller", callers: 1, body: []byte(`{"data":1}`)},
+		{name: "concurrent callers share the body", callers: 5, body: []byte(`{"data":2}`)},
+		{name: "concurrent callers share the error", callers: 5, err: ErrUpstreamUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newQueryCoalescer()
+			release := make(chan struct{})
+			var calls sync.WaitGroup
+			calls.Add(1)
+			fn := func(ctx context.Context) ([]byte, error) {
+				defer calls.Done()
+				<-release
+				return tt.body, tt.err
+			}
+
+			type result struct {
+				body []byte
+				err  error
+			}
+			results := make(chan result, tt.callers)
+			for i := 0; i < tt.callers; i++ {
+				go func() {
+					body, err := q.Do(context.Background(), "key", fn)
+					results <- result{body, err}
+				}()
+			}
+			waitForWaiters(t, q, "key", tt.callers)
+			close(release)
+
+			for i := 0; i < tt.callers; i++ {
+				r := <-results
+				if string(r.body) != string(tt.body) || !errors.Is(r.err, tt.err) {
+					t.Errorf("Do = %q, %v, want %q, %v", r.body, r.err, tt.body, tt.err)
+				}
+			}
+			calls.Wait()
+			if got := q.upstreamCalls.Load(); got != 1 {
+				t.Errorf("upstreamCalls = %d, want 1", got)
+			}
+			if got := q.requests.Load(); got != int64(tt.callers) {
+				t.Errorf("requests = %d, want %d", got, tt.callers)
+			}
+
+			// Finished queries are not cached, the next caller runs fn again
+			calls.Add(1)
+			release = make(chan struct{})
+			close(release)
+			if _, err := q.Do(context.Background(), "key", fn); !errors.Is(err, tt.err) {
+				t.Errorf("Do after the shared query finished = %v, want %v", err, tt.err)
+			}
+			if got := q.upstreamCalls.Load(); got != 2 {
+				t.Errorf("upstreamCalls after the shared query finished = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestQueryCoalescerCancelsAfterLastWaiter(t *testing.T) {
+	q := newQueryCoalescer()
+	sharedDone := make(chan struct{})
+	fn := func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		close(sharedDone)
+		return nil, ctx.Err()
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+	errs := make(chan error, 2)
+	go func() {
+		_, err := q.Do(first, "key", fn)
+		errs <- err
+	}()
+	waitForWaiters(t, q, "key", 1)
+	go func() {
+		_, err := q.Do(second, "key", fn)
+		errs <- err
+	}()
+	waitForWaiters(t, q, "key", 2)
+
+	cancelFirst()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do for the cancelled waiter = %v, want context.Canceled", err)
+	}
+	select {
+	case <-sharedDone:
+		t.Fatal("shared query cancelled while a waiter was left")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancelSecond()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do for the last waiter = %v, want context.Canceled", err)
+	}
+	select {
+	case <-sharedDone:
+	case <-time.After(time.Second):
+		t.Fatal("shared query not cancelled after the last waiter left")
+	}
+
+	q.mu.Lock()
+	_, ok := q.inflight["key"]
+	q.mu.Unlock()
+	if ok {
+		t.Error("abandoned query still in flight, later callers would join it")
+	}
+}
+
+func TestQueryCoalescerCancelledContext(t *testing.T) {
+	q := newQueryCoalescer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	_, err := q.Do(ctx, "key", func(context.Context) ([]byte, error) {
+		called = true
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do with a cancelled context = %v, want context.Canceled", err)
+	}
+	if called || q.upstreamCalls.Load() != 0 {
+		t.Error("Do with a cancelled context started a query")
+	}
+}
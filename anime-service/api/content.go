@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// adultContentKey marks a request context as allowing adult anime
+type adultContentKey struct{}
+
+// WithAdultContent returns a context in which list queries (browse presets, search, recommendations,
+// airing schedule, local lists) include adult anime when include is true.
+// Adult anime are left out of every list by default, so callers that never set this stay safe.
+func WithAdultContent(ctx context.Context, include bool) context.Context {
+	return context.WithValue(ctx, adultContentKey{}, include)
+}
+
+// AdultContentAllowed reports whether ctx was marked with WithAdultContent(ctx, true)
+func AdultContentAllowed(ctx context.Context) bool {
+	include, _ := ctx.Value(adultContentKey{}).(bool)
+	return include
+}
+
+// excludeAdult drops adult anime from results unless ctx allows them.
+// Used where the upstream query can't filter them itself, so pages may come back shorter.
+func excludeAdult(ctx context.Context, animes []models.AnimeCache) []models.AnimeCache {
+	if AdultContentAllowed(ctx) {
+		return animes
+	}
+	kept := animes[:0]
+	for _, a := range animes {
+		if !a.IsAdult {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
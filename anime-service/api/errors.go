@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Error kinds returned by the metadata clients. Callers check them with errors.Is,
+// messages are for logs only and may change.
+var (
+	// ErrNotFound means the requested anime, studio, staff... doesn't exist upstream
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited means the upstream kept rejecting requests after the allowed retries
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstreamUnavailable means the upstream couldn't be reached or failed to answer
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrInvalidInput means the request was rejected because of its parameters
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// UpstreamError describes a non-200 response from a metadata source.
+// It unwraps to the error kind matching its status code.
+type UpstreamError struct {
+	Source     string
+	StatusCode int
+	RetryAfter time.Duration // Set when rate limited
+	Body       string
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return fmt.Sprintf("%s returned status %d (rate limited, retry after %s): %s", e.Source, e.StatusCode, e.RetryAfter, e.Body)
+	}
+	return fmt.Sprintf("%s returned status %d: %s", e.Source, e.StatusCode, e.Body)
+}
+
+// Unwrap maps the status code to an error kind
+func (e *UpstreamError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrInvalidInput
+	default:
+		return ErrUpstreamUnavailable
+	}
+}
+
+// unreachable marks a transport failure (DNS, connection, timeout...) as ErrUpstreamUnavailable
+// while keeping the cause, so context errors still match errors.Is
+func unreachable(source string, err error) error {
+	return fmt.Errorf("%w: request to %s failed: %w", ErrUpstreamUnavailable, source, err)
+}
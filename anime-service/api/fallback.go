@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// IDResolver translates IDs from one source's ID space to another's (e.g. AniList -> MAL)
+type IDResolver interface {
+	ResolveID(ctx context.Context, fromSource string, id int, toSource string) (int, error)
+	// ResolveIDs translates several IDs at once, leaving out the ones without a known mapping
+	ResolveIDs(ctx context.Context, fromSource string, ids []int, toSource string) (map[int]int, error)
+}
+
+// FallbackSource tries its sources in order until one succeeds.
+// The first source is the primary: IDs passed to GetAnimeByID and returned in lists are in its ID space.
+// Other sources are only used through an IDResolver, since callers can't tell ID spaces apart.
+type FallbackSource struct {
+	sources  []MetadataSource
+	resolver IDResolver // Optional, needed to fall back at all
+}
+
+var _ MetadataSource = (*FallbackSource)(nil)
+
+// NewFallbackSource creates a fallback chain, primary first. sources must not be empty.
+func NewFallbackSource(sources []MetadataSource) *FallbackSource {
+	return &FallbackSource{sources: sources}
+}
+
+// SetIDResolver enables falling back to other sources, translating IDs between their ID spaces and the primary's
+func (f *FallbackSource) SetIDResolver(resolver IDResolver) {
+	f.resolver = resolver
+}
+
+// Name returns the name of the primary source
+func (f *FallbackSource) Name() string {
+	return f.sources[0].Name()
+}
+
+// Source returns a configured source by name or alias (e.g. "mal")
+func (f *FallbackSource) Source(name string) (MetadataSource, error) {
+	normalized, err := NormalizeSourceName(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range f.sources {
+		if s.Name() == normalized {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: metadata source %q is not enabled", ErrInvalidInput, normalized)
+}
+
+// GetAnimeByID fetches details from the primary source, then from the others if an IDResolver is set.
+// Results from fallback sources keep the requested ID and are marked with MetadataSource.
+func (f *FallbackSource) GetAnimeByID(ctx context.Context, id int) (*models.AnimeDetails, error) {
+	primary := f.sources[0]
+	details, err := primary.GetAnimeByID(ctx, id)
+	// No point falling back for an anime that doesn't exist, and a cancelled caller doesn't want an answer from any source
+	if err == nil || errors.Is(err, ErrNotFound) || f.resolver == nil || ctx.Err() != nil {
+		return details, err
+	}
+	log.Printf("Warning: %s failed for anime ID %d, trying fallback sources: %v", primary.Name(), id, err)
+
+	firstErr := err
+	for _, source := range f.sources[1:] {
+		foreignID, resolveErr := f.resolver.ResolveID(ctx, primary.Name(), id, source.Name())
+		if resolveErr != nil {
+			continue
+		}
+		fallbackDetails, fallbackErr := source.GetAnimeByID(ctx, foreignID)
+		if fallbackErr != nil {
+			log.Printf("Warning: Fallback source %s failed for anime ID %d (%s ID %d): %v", source.Name(), id, source.Name(), foreignID, fallbackErr)
+			continue
+		}
+		fallbackDetails.ID = id
+		fallbackDetails.MetadataSource = source.Name()
+		return fallbackDetails, nil
+	}
+	return nil, firstErr
+}
+
+// list runs a list operation against the primary source, then against the others if an IDResolver is set.
+// Results from fallback sources are translated to primary IDs, and entries without a known mapping are dropped,
+// so their pages may be shorter than perPage.
+// When no fallback source returns anything usable, the primary's error is returned, so callers can serve
+// their own copy of the list instead.
+func (f *FallbackSource) list(ctx context.Context, operation string, fn func(MetadataSource) ([]models.AnimeCache, int, error)) ([]models.AnimeCache, int, error) {
+	primary := f.sources[0]
+	results, total, err := fn(primary)
+	if err == nil || errors.Is(err, ErrInvalidInput) || f.resolver == nil || ctx.Err() != nil {
+		return results, total, err
+	}
+	log.Printf("Warning: %s failed on %s, trying fallback sources: %v", operation, primary.Name(), err)
+
+	firstErr := err
+	for _, source := range f.sources[1:] {
+		fallbackResults, fallbackTotal, fallbackErr := fn(source)
+		if fallbackErr != nil {
+			if ctx.Err() != nil {
+				return nil, 0, firstErr
+			}
+			log.Printf("Warning: Fallback source %s failed on %s: %v", source.Name(), operation, fallbackErr)
+			continue
+		}
+		translated, resolveErr := f.toPrimaryIDs(ctx, source, fallbackResults)
+		if resolveErr != nil {
+			log.Printf("Warning: Failed to translate %s IDs from %s: %v", operation, source.Name(), resolveErr)
+			continue
+		}
+		if len(translated) == 0 && len(fallbackResults) > 0 {
+			log.Printf("Warning: None of the %d %s results from %s map to %s IDs", len(fallbackResults), operation, source.Name(), primary.Name())
+			continue
+		}
+		// Unmapped entries are left out of the total too, though pages may still come back short
+		return translated, fallbackTotal - (len(fallbackResults) - len(translated)), nil
+	}
+	return nil, 0, firstErr
+}
+
+// toPrimaryIDs rewrites the IDs of a fallback source's results to primary IDs, dropping entries that don't map
+func (f *FallbackSource) toPrimaryIDs(ctx context.Context, source MetadataSource, results []models.AnimeCache) ([]models.AnimeCache, error) {
+	ids := make([]int, len(results))
+	for i, a := range results {
+		ids[i] = a.ID
+	}
+	resolved, err := f.resolver.ResolveIDs(ctx, source.Name(), ids, f.sources[0].Name())
+	if err != nil {
+		return nil, err
+	}
+	translated := make([]models.AnimeCache, 0, len(results))
+	for _, a := range results {
+		primaryID, ok := resolved[a.ID]
+		if !ok {
+			continue
+		}
+		a.ID = primaryID
+		a.MetadataSource = source.Name()
+		translated = append(translated, a)
+	}
+	return translated, nil
+}
+
+func (f *FallbackSource) SearchAnime(ctx context.Context, query string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return f.list(ctx, "search", func(s MetadataSource) ([]models.AnimeCache, int, error) {
+		return s.SearchAnime(ctx, query, page, perPage)
+	})
+}
+
+func (f *FallbackSource) GetPopularAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return f.list(ctx, "popular", func(s MetadataSource) ([]models.AnimeCache, int, error) {
+		return s.GetPopularAnime(ctx, page, perPage)
+	})
+}
+
+func (f *FallbackSource) GetTrendingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return f.list(ctx, "trending", func(s MetadataSource) ([]models.AnimeCache, int, error) {
+		return s.GetTrendingAnime(ctx, page, perPage)
+	})
+}
+
+func (f *FallbackSource) GetAnimeBySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return f.list(ctx, "season", func(s MetadataSource) ([]models.AnimeCache, int, error) {
+		return s.GetAnimeBySeason(ctx, year, season, page, perPage)
+	})
+}
+
+func (f *FallbackSource) GetUpcomingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return f.list(ctx, "upcoming", func(s MetadataSource) ([]models.AnimeCache, int, error) {
+		return s.GetUpcomingAnime(ctx, page, perPage)
+	})
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Modes for the AniList client, selected with ANILIST_MODE.
+// Only AniList requests are recorded and replayed: Jikan and Kitsu always go to the network,
+// so replay mode leaves them out of METADATA_SOURCES.
+const (
+	ModeLive   = "live"   // Talk to AniList only (default)
+	ModeRecord = "record" // Talk to AniList and save every successful request/response pair
+	ModeReplay = "replay" // Never talk to AniList, answer from saved fixtures
+)
+
+// DefaultFixturesDir is where fixtures are stored when ANILIST_FIXTURES_DIR is not set
+const DefaultFixturesDir = "fixtures/anilist"
+
+// ErrFixtureNotFound is returned in replay mode when no fixture matches a request.
+// Loads wrap it with ErrUpstreamUnavailable, since a missing fixture is replay's equivalent of an outage.
+var ErrFixtureNotFound = errors.New("no recorded fixture for request")
+
+// modeFromEnv reads ANILIST_MODE, defaulting to ModeLive
+func modeFromEnv() string {
+	mode := strings.ToLower(os.Getenv("ANILIST_MODE"))
+	switch mode {
+	case "":
+		return ModeLive
+	case ModeLive, ModeRecord, ModeReplay:
+		return mode
+	default:
+		log.Printf("Warning: Unknown ANILIST_MODE %q, using %s", mode, ModeLive)
+		return ModeLive
+	}
+}
+
+// fixture is the on-disk format of a recorded GraphQL exchange
+type fixture struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+	Response  json.RawMessage        `json:"response"`
+}
+
+// fixtureStore saves and loads fixtures keyed by a hash of the request
+type fixtureStore struct {
+	dir string
+	mu  sync.Mutex // Serializes writes so concurrent recordings of the same request don't interleave
+}
+
+func newFixtureStore(dir string) *fixtureStore {
+	return &fixtureStore{dir: dir}
+}
+
+// requestHash identifies a request independently of query whitespace and variable ordering
+func requestHash(query string, variables map[string]interface{}) (string, error) {
+	// json.Marshal sorts map keys, so equal variables always encode the same way
+	vars, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+	normalized := strings.Join(strings.Fields(query), " ")
+	sum := sha256.Sum256(append([]byte(normalized+"\n"), vars...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (s *fixtureStore) path(hash string) string {
+	return filepath.Join(s.dir, hash+".json")
+}
+
+// Load returns the recorded response body for a request
+func (s *fixtureStore) Load(query string, variables map[string]interface{}) ([]byte, error) {
+	hash, err := requestHash(query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash request: %w", err)
+	}
+	data, err := os.ReadFile(s.path(hash))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %w %s (dir %s)", ErrUpstreamUnavailable, ErrFixtureNotFound, hash, s.dir)
+		}
+		return nil, fmt.Errorf("failed to read fixture %s: %w", hash, err)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", hash, err)
+	}
+	return f.Response, nil
+}
+
+// Save records a response body for a request, overwriting any previous recording
+func (s *fixtureStore) Save(query string, variables map[string]interface{}, response []byte) error {
+	hash, err := requestHash(query, variables)
+	if err != nil {
+		return fmt.Errorf("failed to hash request: %w", err)
+	}
+	data, err := json.MarshalIndent(fixture{Query: query, Variables: variables, Response: response}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture %s: %w", hash, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create fixtures dir: %w", err)
+	}
+	// Write to a temp file first so a crash never leaves a half-written fixture behind
+	tmp := s.path(hash) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture %s: %w", hash, err)
+	}
+	return os.Rename(tmp, s.path(hash))
+}
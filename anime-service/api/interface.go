@@ -1,17 +1,32 @@
 package api
 
-import "github.com/vrstep/wawatch-backend/models"
+import (
+	"context"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
 
 // AniListAPI defines the interface for AniList client operations
 type AniListAPI interface {
-	GetAnimeByID(id int) (*models.AnimeDetails, error)
-	SearchAnime(query string, page int, perPage int) ([]models.AnimeCache, int, error)
-	GetPopularAnime(page int, perPage int) ([]models.AnimeCache, int, error)
-	GetTrendingAnime(page int, perPage int) ([]models.AnimeCache, int, error)
-	GetAnimeBySeason(year int, season string, page int, perPage int) ([]models.AnimeCache, int, error)
-	GetUpcomingAnime(page int, perPage int) ([]models.AnimeCache, int, error)
-	GetRecentlyReleasedAnime(page int, perPage int) ([]models.AnimeCache, int, error)
-	GetAnimeByTags(tags []string, page int, perPage int) ([]models.AnimeCache, int, error) // Modified
+	GetAnimeByID(ctx context.Context, id int) (*models.AnimeDetails, error)
+	SearchAnime(ctx context.Context, query string, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetPopularAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetTrendingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetAnimeBySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetUpcomingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetRecentlyReleasedAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetAnimeByTags(ctx context.Context, tags []string, excludedTags []string, minTagRank *int, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetAnimeByIDs(ctx context.Context, ids []int) ([]models.AnimeCache, error) // Order of ids, unknown IDs left out
+	ResolveMalIDs(ctx context.Context, malIDs []int) (map[int]int, error)      // MAL ID -> AniList ID
+	GetAnimeCharacters(ctx context.Context, id int, language string, page int, perPage int) ([]models.CharacterEdge, int, error)
+	GetStudio(ctx context.Context, id int, page int, perPage int) (*models.Studio, []models.AnimeWork, int, error)
+	GetStaff(ctx context.Context, id int, works string, page int, perPage int) (*models.Staff, []models.AnimeWork, int, error)
+	GetAnimeRelations(ctx context.Context, id int) (*models.RelatedMedia, []models.RelationEdge, error)
+	GetAnimeRecommendations(ctx context.Context, id int, page int, perPage int) ([]models.Recommendation, int, error)
+	BrowseAnime(ctx context.Context, filter BrowseFilter, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetGenres(ctx context.Context) ([]string, error)
+	GetTags(ctx context.Context) ([]models.MediaTag, error)
+	GetAiringSchedule(ctx context.Context, from int64, to int64, page int, perPage int) ([]models.AiringScheduleEntry, int, error)
 }
 
 // Ensure the real client implements the interface
@@ -0,0 +1,304 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+const (
+	// JikanURL is the default Jikan (unofficial MyAnimeList) API endpoint, override with JIKAN_URL
+	JikanURL = "https://api.jikan.moe/v4"
+	// Jikan allows 60 requests per minute (and 3 per second)
+	jikanRateLimitPerMinute = 60
+	// Jikan caps page sizes at 25
+	jikanMaxPerPage = 25
+)
+
+// JikanClient reads MyAnimeList data through the Jikan API. IDs are MAL IDs.
+type JikanClient struct {
+	httpClient *http.Client
+	baseURL    string
+	limiter    *rateLimiter
+}
+
+var _ MetadataSource = (*JikanClient)(nil)
+
+// NewJikanClient creates a new client for the Jikan API
+func NewJikanClient() *JikanClient {
+	baseURL := os.Getenv("JIKAN_URL")
+	if baseURL == "" {
+		baseURL = JikanURL
+	}
+	return &JikanClient{
+		httpClient: &http.Client{Timeout: time.Second * DefaultTimeout},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		limiter:    newRateLimiter(jikanRateLimitPerMinute),
+	}
+}
+
+func (c *JikanClient) Name() string { return SourceJikan }
+
+// jikanDate is the "prop" form of a Jikan date, with nullable parts
+type jikanDate struct {
+	Day   *int `json:"day"`
+	Month *int `json:"month"`
+	Year  *int `json:"year"`
+}
+
+// jikanAnime is the subset of the Jikan anime resource we map
+type jikanAnime struct {
+	MalID         int      `json:"mal_id"`
+	Title         string   `json:"title"`
+	TitleEnglish  string   `json:"title_english"`
+	TitleJapanese string   `json:"title_japanese"`
+	TitleSynonyms []string `json:"title_synonyms"`
+	Synopsis      string   `json:"synopsis"`
+	Source        string   `json:"source"` // e.g. "Manga", "Light novel", "Original"
+	Rating        string   `json:"rating"` // e.g. "PG-13 - Teens 13 or older", "Rx - Hentai"
+	Type          string   `json:"type"`
+	Status        string   `json:"status"`
+	Episodes      *int     `json:"episodes"`
+	Duration      string   `json:"duration"` // e.g. "24 min per ep", "1 hr 50 min"
+	Genres        []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	Aired struct {
+		Prop struct {
+			From jikanDate `json:"from"`
+			To   jikanDate `json:"to"`
+		} `json:"prop"`
+	} `json:"aired"`
+	Season string `json:"season"`
+	Year   int    `json:"year"`
+	Images struct {
+		JPG struct {
+			ImageURL      string `json:"image_url"`
+			LargeImageURL string `json:"large_image_url"`
+		} `json:"jpg"`
+	} `json:"images"`
+	Trailer struct {
+		YoutubeID string `json:"youtube_id"`
+		Images    struct {
+			ImageURL string `json:"image_url"`
+		} `json:"images"`
+	} `json:"trailer"`
+	Score     float64 `json:"score"` // 0-10
+	Members   int     `json:"members"`
+	Favorites int     `json:"favorites"`
+	Studios   []struct {
+		Name string `json:"name"`
+	} `json:"studios"`
+}
+
+type jikanPagination struct {
+	LastVisiblePage int  `json:"last_visible_page"`
+	HasNextPage     bool `json:"has_next_page"`
+	Items           struct {
+		Total int `json:"total"`
+	} `json:"items"`
+}
+
+// GetAnimeByID fetches anime details by MAL ID
+func (c *JikanClient) GetAnimeByID(ctx context.Context, id int) (*models.AnimeDetails, error) {
+	var result struct {
+		Data *jikanAnime `json:"data"`
+	}
+	if err := restGet(ctx, c.httpClient, c.limiter, fmt.Sprintf("%s/anime/%d/full", c.baseURL, id), &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch anime by MAL ID %d: %w", id, err)
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("%w: no anime data returned for MAL ID %d", ErrNotFound, id)
+	}
+	return result.Data.toAnimeDetails(), nil
+}
+
+// SearchAnime searches MyAnimeList by title
+func (c *JikanClient) SearchAnime(ctx context.Context, query string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	params := url.Values{"q": {query}, "order_by": {"members"}, "sort": {"desc"}}
+	return c.list(ctx, "/anime", params, page, perPage)
+}
+
+// GetPopularAnime fetches the most popular anime on MyAnimeList
+func (c *JikanClient) GetPopularAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, "/top/anime", url.Values{"filter": {"bypopularity"}}, page, perPage)
+}
+
+// GetTrendingAnime approximates trending with MAL's top currently airing anime
+func (c *JikanClient) GetTrendingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, "/top/anime", url.Values{"filter": {"airing"}}, page, perPage)
+}
+
+// GetAnimeBySeason fetches anime by year and season (WINTER, SPRING, SUMMER, FALL)
+func (c *JikanClient) GetAnimeBySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, fmt.Sprintf("/seasons/%d/%s", year, strings.ToLower(season)), url.Values{}, page, perPage)
+}
+
+// GetUpcomingAnime fetches anime that have not aired yet
+func (c *JikanClient) GetUpcomingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, "/seasons/upcoming", url.Values{}, page, perPage)
+}
+
+// list runs a paged Jikan list endpoint. Jikan pages hold at most jikanMaxPerPage entries,
+// so larger pages are assembled from the Jikan pages covering the same range of entries.
+func (c *JikanClient) list(ctx context.Context, path string, params url.Values, page int, perPage int) ([]models.AnimeCache, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = jikanMaxPerPage
+	}
+	limit := min(perPage, jikanMaxPerPage)
+	start := (page - 1) * perPage
+	skip := start % limit // Entries of the first Jikan page that belong to the previous page
+	params.Set("limit", strconv.Itoa(limit))
+	if !AdultContentAllowed(ctx) {
+		params.Set("sfw", "true") // Leaves out hentai (Rx rated) entries
+	}
+
+	animes := []models.AnimeCache{}
+	total := 0
+	for jikanPage := start/limit + 1; len(animes) < skip+perPage; jikanPage++ {
+		params.Set("page", strconv.Itoa(jikanPage))
+		var result struct {
+			Data       []jikanAnime    `json:"data"`
+			Pagination jikanPagination `json:"pagination"`
+		}
+		if err := restGet(ctx, c.httpClient, c.limiter, c.baseURL+path+"?"+params.Encode(), &result); err != nil {
+			return nil, 0, fmt.Errorf("jikan request %s failed: %w", path, err)
+		}
+		for _, a := range result.Data {
+			animes = append(animes, a.toAnimeCache())
+		}
+		total = result.Pagination.Items.Total
+		if !result.Pagination.HasNextPage || len(result.Data) < limit {
+			break
+		}
+	}
+	animes = animes[min(skip, len(animes)):]
+	return animes[:min(perPage, len(animes))], total, nil
+}
+
+// jikanStatuses maps MAL airing statuses to AniList's MediaStatus values
+var jikanStatuses = map[string]string{
+	"Finished Airing":  "FINISHED",
+	"Currently Airing": "RELEASING",
+	"Not yet aired":    "NOT_YET_RELEASED",
+}
+
+// jikanFormat maps MAL types (TV, Movie, Special, ...) to AniList's MediaFormat values
+func jikanFormat(t string) string {
+	switch t {
+	case "TV Special":
+		return "SPECIAL"
+	case "":
+		return ""
+	default:
+		return strings.ToUpper(strings.ReplaceAll(t, " ", "_"))
+	}
+}
+
+// jikanSource maps MAL sources ("Light novel", "Web manga", ...) to AniList's MediaSource values where they match
+func jikanSource(source string) string {
+	switch source {
+	case "", "Unknown":
+		return ""
+	case "4-koma manga", "Web manga":
+		return "MANGA"
+	case "Book":
+		return "NOVEL"
+	case "Card game":
+		return "GAME"
+	default:
+		return strings.ToUpper(strings.ReplaceAll(source, " ", "_"))
+	}
+}
+
+var jikanDurationPattern = regexp.MustCompile(`(?:(\d+)\s*hr)?\s*(?:(\d+)\s*min)?`)
+
+// jikanDurationMinutes parses strings like "24 min per ep" or "1 hr 50 min"
+func jikanDurationMinutes(s string) int {
+	m := jikanDurationPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	return hours*60 + minutes
+}
+
+func derefInt(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func (a *jikanAnime) toAnimeDetails() *models.AnimeDetails {
+	d := &models.AnimeDetails{
+		ID:             a.MalID,
+		Description:    a.Synopsis,
+		Format:         jikanFormat(a.Type),
+		Status:         jikanStatuses[a.Status],
+		Episodes:       derefInt(a.Episodes),
+		Duration:       jikanDurationMinutes(a.Duration),
+		Season:         strings.ToUpper(a.Season),
+		SeasonYear:     a.Year,
+		AverageScore:   int(a.Score * 10),
+		Popularity:     a.Members,
+		Favourites:     a.Favorites,
+		Synonyms:       a.TitleSynonyms,
+		Source:         jikanSource(a.Source),
+		IsAdult:        strings.HasPrefix(a.Rating, "Rx"),
+		MetadataSource: SourceJikan,
+	}
+	if a.Trailer.YoutubeID != "" {
+		d.Trailer = &models.Trailer{ID: a.Trailer.YoutubeID, Site: "youtube", Thumbnail: a.Trailer.Images.ImageURL}
+	}
+	d.Title.Romaji = a.Title
+	d.Title.English = a.TitleEnglish
+	d.Title.Native = a.TitleJapanese
+	d.Genres = make([]string, len(a.Genres))
+	for i, g := range a.Genres {
+		d.Genres[i] = g.Name
+	}
+	d.StartDate.Year = derefInt(a.Aired.Prop.From.Year)
+	d.StartDate.Month = derefInt(a.Aired.Prop.From.Month)
+	d.StartDate.Day = derefInt(a.Aired.Prop.From.Day)
+	d.EndDate.Year = derefInt(a.Aired.Prop.To.Year)
+	d.EndDate.Month = derefInt(a.Aired.Prop.To.Month)
+	d.EndDate.Day = derefInt(a.Aired.Prop.To.Day)
+	d.CoverImage.Large = a.Images.JPG.LargeImageURL
+	d.CoverImage.Medium = a.Images.JPG.ImageURL
+	for _, s := range a.Studios {
+		d.Studios.Nodes = append(d.Studios.Nodes, models.StudioNode{Name: s.Name, IsAnimationStudio: true})
+	}
+	return d
+}
+
+func (a *jikanAnime) toAnimeCache() models.AnimeCache {
+	title := a.TitleEnglish
+	if title == "" {
+		title = a.Title
+	}
+	if title == "" {
+		title = a.TitleJapanese
+	}
+	return models.AnimeCache{
+		ID:             a.MalID,
+		Title:          title,
+		CoverImage:     a.Images.JPG.LargeImageURL,
+		Format:         jikanFormat(a.Type),
+		TotalEpisodes:  a.Episodes,
+		IsAdult:        strings.HasPrefix(a.Rating, "Rx"),
+		MetadataSource: SourceJikan,
+	}
+}
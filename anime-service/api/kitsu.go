@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+const (
+	// KitsuURL is the default Kitsu API endpoint, override with KITSU_URL
+	KitsuURL = "https://kitsu.io/api/edge"
+	// Kitsu doesn't publish a hard limit, stay polite
+	kitsuRateLimitPerMinute = 60
+	// Kitsu caps page[limit] at 20
+	kitsuMaxPerPage = 20
+)
+
+// KitsuClient reads anime data from the Kitsu JSON:API. IDs are Kitsu IDs.
+type KitsuClient struct {
+	httpClient *http.Client
+	baseURL    string
+	limiter    *rateLimiter
+}
+
+var _ MetadataSource = (*KitsuClient)(nil)
+
+// NewKitsuClient creates a new client for the Kitsu API
+func NewKitsuClient() *KitsuClient {
+	baseURL := os.Getenv("KITSU_URL")
+	if baseURL == "" {
+		baseURL = KitsuURL
+	}
+	return &KitsuClient{
+		httpClient: &http.Client{Timeout: time.Second * DefaultTimeout},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		limiter:    newRateLimiter(kitsuRateLimitPerMinute),
+	}
+}
+
+func (c *KitsuClient) Name() string { return SourceKitsu }
+
+// kitsuAnime is a Kitsu "anime" resource object
+type kitsuAnime struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		CanonicalTitle string `json:"canonicalTitle"`
+		Titles         struct {
+			En   string `json:"en"`
+			EnJp string `json:"en_jp"`
+			JaJp string `json:"ja_jp"`
+		} `json:"titles"`
+		AbbreviatedTitles []string `json:"abbreviatedTitles"`
+		Synopsis          string   `json:"synopsis"`
+		Subtype           string   `json:"subtype"` // TV, movie, OVA, ONA, special, music
+		Status            string   `json:"status"`  // current, finished, tba, unreleased, upcoming
+		EpisodeCount      *int     `json:"episodeCount"`
+		EpisodeLength     *int     `json:"episodeLength"`
+		StartDate         string   `json:"startDate"` // YYYY-MM-DD
+		EndDate           string   `json:"endDate"`
+		AverageRating     string   `json:"averageRating"` // "82.35", 0-100
+		UserCount         int      `json:"userCount"`
+		FavoritesCount    int      `json:"favoritesCount"`
+		NSFW              bool     `json:"nsfw"`
+		YoutubeVideoID    string   `json:"youtubeVideoId"`
+		PosterImage       *struct {
+			Medium string `json:"medium"`
+			Large  string `json:"large"`
+		} `json:"posterImage"`
+		CoverImage *struct {
+			Original string `json:"original"`
+		} `json:"coverImage"`
+	} `json:"attributes"`
+}
+
+// kitsuIncluded is a sideloaded resource (we only include categories)
+type kitsuIncluded struct {
+	Type       string `json:"type"`
+	Attributes struct {
+		Title string `json:"title"`
+	} `json:"attributes"`
+}
+
+// GetAnimeByID fetches anime details by Kitsu ID
+func (c *KitsuClient) GetAnimeByID(ctx context.Context, id int) (*models.AnimeDetails, error) {
+	var result struct {
+		Data     *kitsuAnime     `json:"data"`
+		Included []kitsuIncluded `json:"included"`
+	}
+	if err := restGet(ctx, c.httpClient, c.limiter, fmt.Sprintf("%s/anime/%d?include=categories", c.baseURL, id), &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch anime by Kitsu ID %d: %w", id, err)
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("%w: no anime data returned for Kitsu ID %d", ErrNotFound, id)
+	}
+
+	details := result.Data.toAnimeDetails()
+	for _, inc := range result.Included {
+		if inc.Type == "categories" {
+			details.Genres = append(details.Genres, inc.Attributes.Title)
+		}
+	}
+	return details, nil
+}
+
+// SearchAnime searches Kitsu by title
+func (c *KitsuClient) SearchAnime(ctx context.Context, query string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, url.Values{"filter[text]": {query}}, page, perPage)
+}
+
+// GetPopularAnime fetches anime ordered by Kitsu's popularity rank
+func (c *KitsuClient) GetPopularAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, url.Values{"sort": {"popularityRank"}}, page, perPage)
+}
+
+// GetTrendingAnime approximates trending with the most popular currently airing anime.
+// Kitsu's /trending/anime endpoint is not paginated.
+func (c *KitsuClient) GetTrendingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, url.Values{"filter[status]": {"current"}, "sort": {"popularityRank"}}, page, perPage)
+}
+
+// GetAnimeBySeason fetches anime by year and season (WINTER, SPRING, SUMMER, FALL)
+func (c *KitsuClient) GetAnimeBySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	params := url.Values{
+		"filter[season]":     {strings.ToLower(season)},
+		"filter[seasonYear]": {strconv.Itoa(year)},
+		"sort":               {"popularityRank"},
+	}
+	return c.list(ctx, params, page, perPage)
+}
+
+// GetUpcomingAnime fetches anime that have not aired yet
+func (c *KitsuClient) GetUpcomingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return c.list(ctx, url.Values{"filter[status]": {"upcoming,unreleased"}, "sort": {"popularityRank"}}, page, perPage)
+}
+
+// list runs a paged query against /anime. Kitsu pages hold at most kitsuMaxPerPage entries,
+// so larger pages are assembled from several Kitsu requests starting at the same offset.
+func (c *KitsuClient) list(ctx context.Context, params url.Values, page int, perPage int) ([]models.AnimeCache, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = kitsuMaxPerPage
+	}
+	start := (page - 1) * perPage
+
+	animes := []models.AnimeCache{}
+	total := 0
+	for fetched := 0; fetched < perPage; {
+		limit := min(perPage-fetched, kitsuMaxPerPage)
+		params.Set("page[limit]", strconv.Itoa(limit))
+		params.Set("page[offset]", strconv.Itoa(start+fetched))
+		var result struct {
+			Data []kitsuAnime `json:"data"`
+			Meta struct {
+				Count int `json:"count"`
+			} `json:"meta"`
+		}
+		if err := restGet(ctx, c.httpClient, c.limiter, c.baseURL+"/anime?"+params.Encode(), &result); err != nil {
+			return nil, 0, fmt.Errorf("kitsu request failed: %w", err)
+		}
+		for _, a := range result.Data {
+			animes = append(animes, a.toAnimeCache())
+		}
+		total = result.Meta.Count
+		fetched += len(result.Data)
+		if len(result.Data) < limit {
+			break
+		}
+	}
+	// Kitsu's API has no parameter to leave NSFW entries out
+	return excludeAdult(ctx, animes), total, nil
+}
+
+// kitsuStatuses maps Kitsu statuses to AniList's MediaStatus values
+var kitsuStatuses = map[string]string{
+	"finished":   "FINISHED",
+	"current":    "RELEASING",
+	"upcoming":   "NOT_YET_RELEASED",
+	"unreleased": "NOT_YET_RELEASED",
+	"tba":        "NOT_YET_RELEASED",
+}
+
+// kitsuFormat maps Kitsu subtypes to AniList's MediaFormat values
+func kitsuFormat(subtype string) string {
+	return strings.ToUpper(subtype)
+}
+
+// kitsuSeason derives the anime season from its start month, Kitsu doesn't return it directly
+func kitsuSeason(month int) string {
+	switch month {
+	case 1, 2, 3:
+		return "WINTER"
+	case 4, 5, 6:
+		return "SPRING"
+	case 7, 8, 9:
+		return "SUMMER"
+	case 10, 11, 12:
+		return "FALL"
+	default:
+		return ""
+	}
+}
+
+// parseKitsuDate splits a YYYY-MM-DD date, returning zeros for missing parts
+func parseKitsuDate(s string) (year, month, day int) {
+	parts := strings.SplitN(s, "-", 3)
+	if len(parts) > 0 {
+		year, _ = strconv.Atoi(parts[0])
+	}
+	if len(parts) > 1 {
+		month, _ = strconv.Atoi(parts[1])
+	}
+	if len(parts) > 2 {
+		day, _ = strconv.Atoi(parts[2])
+	}
+	return year, month, day
+}
+
+func (a *kitsuAnime) toAnimeDetails() *models.AnimeDetails {
+	attrs := a.Attributes
+	id, _ := strconv.Atoi(a.ID)
+	score, _ := strconv.ParseFloat(attrs.AverageRating, 64)
+
+	d := &models.AnimeDetails{
+		ID:             id,
+		Description:    attrs.Synopsis,
+		Format:         kitsuFormat(attrs.Subtype),
+		Status:         kitsuStatuses[attrs.Status],
+		Episodes:       derefInt(attrs.EpisodeCount),
+		Duration:       derefInt(attrs.EpisodeLength),
+		AverageScore:   int(score),
+		Popularity:     attrs.UserCount,
+		Favourites:     attrs.FavoritesCount,
+		Synonyms:       attrs.AbbreviatedTitles,
+		IsAdult:        attrs.NSFW,
+		MetadataSource: SourceKitsu,
+	}
+	if attrs.YoutubeVideoID != "" {
+		d.Trailer = &models.Trailer{ID: attrs.YoutubeVideoID, Site: "youtube"}
+	}
+	d.Title.Romaji = attrs.Titles.EnJp
+	if d.Title.Romaji == "" {
+		d.Title.Romaji = attrs.CanonicalTitle
+	}
+	d.Title.English = attrs.Titles.En
+	d.Title.Native = attrs.Titles.JaJp
+	d.StartDate.Year, d.StartDate.Month, d.StartDate.Day = parseKitsuDate(attrs.StartDate)
+	d.EndDate.Year, d.EndDate.Month, d.EndDate.Day = parseKitsuDate(attrs.EndDate)
+	d.Season = kitsuSeason(d.StartDate.Month)
+	d.SeasonYear = d.StartDate.Year
+	if attrs.PosterImage != nil {
+		d.CoverImage.Large = attrs.PosterImage.Large
+		d.CoverImage.Medium = attrs.PosterImage.Medium
+	}
+	if attrs.CoverImage != nil {
+		d.BannerImage = attrs.CoverImage.Original
+	}
+	return d
+}
+
+func (a *kitsuAnime) toAnimeCache() models.AnimeCache {
+	attrs := a.Attributes
+	id, _ := strconv.Atoi(a.ID)
+	title := attrs.Titles.En
+	if title == "" {
+		title = attrs.CanonicalTitle
+	}
+	cache := models.AnimeCache{
+		ID:             id,
+		Title:          title,
+		Format:         kitsuFormat(attrs.Subtype),
+		TotalEpisodes:  attrs.EpisodeCount,
+		IsAdult:        attrs.NSFW,
+		MetadataSource: SourceKitsu,
+	}
+	if attrs.PosterImage != nil {
+		cache.CoverImage = attrs.PosterImage.Large
+	}
+	return cache
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// AniList allows 90 requests per minute per IP (may be lowered during degraded service)
+	DefaultRateLimitPerMinute = 90
+	// Keep a few requests in reserve so bursts from other callers on the same IP don't trip a 429
+	rateLimitReserve = 2
+	// Used when a 429 carries neither Retry-After nor X-RateLimit-Reset
+	defaultRetryAfter = 60 * time.Second
+)
+
+// rateLimiter is a token bucket shared by every goroutine using an AniListClient.
+// It refills continuously and is corrected with the X-RateLimit-* headers AniList returns.
+type rateLimiter struct {
+	mu           sync.Mutex
+	capacity     float64
+	tokens       float64
+	refillPerSec float64
+	last         time.Time
+	blockedUntil time.Time // Set after a 429, no requests go out before this
+}
+
+func newRateLimiter(perMinute int) *rateLimiter {
+	if perMinute <= 0 {
+		perMinute = DefaultRateLimitPerMinute
+	}
+	return &rateLimiter{
+		capacity:     float64(perMinute),
+		tokens:       float64(perMinute),
+		refillPerSec: float64(perMinute) / 60,
+		last:         time.Now(),
+	}
+}
+
+// refill adds the tokens earned since the last call. Caller must hold mu.
+func (l *rateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed > 0 {
+		l.tokens += elapsed * l.refillPerSec
+		if l.tokens > l.capacity {
+			l.tokens = l.capacity
+		}
+		l.last = now
+	}
+}
+
+// Wait blocks until a request may be sent, then takes a token.
+// It gives up without taking a token when ctx is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.refill(now)
+
+		var wait time.Duration
+		switch {
+		case now.Before(l.blockedUntil):
+			wait = l.blockedUntil.Sub(now)
+		case l.tokens >= 1:
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		default:
+			wait = time.Duration((1 - l.tokens) / l.refillPerSec * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Observe syncs the bucket with the rate limit headers of an AniList response
+func (l *rateLimiter) Observe(header http.Header) {
+	limit, errLimit := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+
+	// AniList lowers the limit when degraded, follow it
+	if errLimit == nil && limit > 0 && float64(limit) != l.capacity {
+		l.capacity = float64(limit)
+		l.refillPerSec = float64(limit) / 60
+	}
+	if errRemaining == nil {
+		allowed := float64(remaining - rateLimitReserve)
+		if allowed < 0 {
+			allowed = 0
+		}
+		// Only ever lower our estimate, the server knows about requests we don't
+		if allowed < l.tokens {
+			l.tokens = allowed
+		}
+	}
+}
+
+// BlockFor stops all requests for the given duration (after a 429)
+func (l *rateLimiter) BlockFor(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := time.Now().Add(d)
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+	l.tokens = 0
+}
+
+// retryAfter reads the server's back-off hint from a 429 response
+func retryAfter(header http.Header) time.Duration {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header.Get("Retry-After")); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if d := time.Until(time.Unix(reset, 0)); d > 0 {
+			return d
+		}
+	}
+	return defaultRetryAfter
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	l := newRateLimiter(3)
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait %d: unexpected error %v", i, err)
+		}
+	}
+
+	// The bucket is empty and the next token is 20s away
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait on an empty bucket = %v, want context.DeadlineExceeded", err)
+	}
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < 0 {
+		t.Errorf("Wait took a token after giving up, tokens = %v", tokens)
+	}
+}
+
+func TestRateLimiterBlockFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocks  []time.Duration
+		timeout time.Duration
+		wantErr error
+	}{
+		{name: "waits out the block", blocks: []time.Duration{30 * time.Millisecond}, timeout: time.Second},
+		{name: "cancelled while blocked", blocks: []time.Duration{time.Hour}, timeout: 20 * time.Millisecond, wantErr: context.DeadlineExceeded},
+		{name: "shorter block keeps the longer one", blocks: []time.Duration{time.Hour, time.Millisecond}, timeout: 20 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(6000) // A token every 10ms once the block is over
+			started := time.Now()
+			for _, d := range tt.blocks {
+				l.BlockFor(d)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			err := l.Wait(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Wait = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && time.Since(started) < tt.blocks[0] {
+				t.Errorf("Wait returned after %s, before the %s block ended", time.Since(started), tt.blocks[0])
+			}
+		})
+	}
+}
+
+func TestRateLimiterWaitCancelledContext(t *testing.T) {
+	l := newRateLimiter(60)
+	l.BlockFor(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait with a cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		wantCapacity float64
+		wantTokens   float64
+	}{
+		{name: "no headers", headers: map[string]string{}, wantCapacity: 90, wantTokens: 90},
+		{name: "remaining lowers tokens with a reserve", headers: map[string]string{"X-RateLimit-Remaining": "10"}, wantCapacity: 90, wantTokens: 10 - rateLimitReserve},
+		{name: "remaining never raises tokens", headers: map[string]string{"X-RateLimit-Remaining": "500"}, wantCapacity: 90, wantTokens: 90},
+		{name: "remaining below the reserve", headers: map[string]string{"X-RateLimit-Remaining": "1"}, wantCapacity: 90, wantTokens: 0},
+		{name: "degraded limit", headers: map[string]string{"X-RateLimit-Limit": "30", "X-RateLimit-Remaining": "20"}, wantCapacity: 30, wantTokens: 20 - rateLimitReserve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(90)
+			header := http.Header{}
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+			l.Observe(header)
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.capacity != tt.wantCapacity {
+				t.Errorf("capacity = %v, want %v", l.capacity, tt.wantCapacity)
+			}
+			// Refill may add a fraction of a token between the calls
+			if l.tokens < tt.wantTokens || l.tokens > tt.wantTokens+0.1 {
+				t.Errorf("tokens = %v, want %v", l.tokens, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{name: "seconds", headers: map[string]string{"Retry-After": "42"}, want: 42 * time.Second},
+		{name: "nothing", headers: map[string]string{}, want: defaultRetryAfter},
+		{name: "garbage", headers: map[string]string{"Retry-After": "soon"}, want: defaultRetryAfter},
+		{name: "reset in the past", headers: map[string]string{"X-RateLimit-Reset": "1"}, want: defaultRetryAfter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+			if got := retryAfter(header); got != tt.want {
+				t.Errorf("retryAfter = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
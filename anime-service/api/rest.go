@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// restGet performs a rate-limited GET against a REST metadata source and decodes the JSON body into out
+func restGet(ctx context.Context, httpClient *http.Client, limiter *rateLimiter, url string, out interface{}) error {
+	for attempt := 0; ; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return unreachable(url, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return unreachable(url, err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			wait := retryAfter(resp.Header)
+			limiter.BlockFor(wait)
+			if attempt >= MaxRateLimitRetries || wait > MaxRetryWait {
+				return &UpstreamError{Source: url, StatusCode: resp.StatusCode, RetryAfter: wait, Body: string(body)}
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return &UpstreamError{Source: url, StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("%w: failed to parse response from %s: %w", ErrUpstreamUnavailable, url, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// Names of the supported metadata sources
+const (
+	SourceAniList = "anilist"
+	SourceJikan   = "jikan" // MyAnimeList data through the Jikan API, IDs are MAL IDs
+	SourceKitsu   = "kitsu"
+)
+
+// DefaultSourceOrder is used when METADATA_SOURCES is not set
+var DefaultSourceOrder = []string{SourceAniList, SourceJikan, SourceKitsu}
+
+// MetadataSource is the subset of anime metadata operations every backing database can serve.
+// IDs are always in the source's own ID space (AniList ID, MAL ID, Kitsu ID).
+type MetadataSource interface {
+	Name() string
+	GetAnimeByID(ctx context.Context, id int) (*models.AnimeDetails, error)
+	SearchAnime(ctx context.Context, query string, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetPopularAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetTrendingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetAnimeBySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error)
+	GetUpcomingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error)
+}
+
+// aniListSource adapts an AniListAPI to MetadataSource
+type aniListSource struct {
+	AniListAPI
+}
+
+// NewAniListSource wraps an AniList client (real or mock) as a MetadataSource
+func NewAniListSource(client AniListAPI) MetadataSource {
+	return aniListSource{client}
+}
+
+func (aniListSource) Name() string { return SourceAniList }
+
+// NormalizeSourceName maps user-facing aliases (e.g. "mal") to a source name
+func NormalizeSourceName(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case SourceAniList, "al":
+		return SourceAniList, nil
+	case SourceJikan, "mal", "myanimelist":
+		return SourceJikan, nil
+	case SourceKitsu:
+		return SourceKitsu, nil
+	default:
+		return "", fmt.Errorf("%w: unknown metadata source %q", ErrInvalidInput, name)
+	}
+}
+
+// NewSourceFromName builds a standalone source. AniList sources wrap the given client.
+func NewSourceFromName(name string, anilist AniListAPI) (MetadataSource, error) {
+	normalized, err := NormalizeSourceName(name)
+	if err != nil {
+		return nil, err
+	}
+	switch normalized {
+	case SourceJikan:
+		return NewJikanClient(), nil
+	case SourceKitsu:
+		return NewKitsuClient(), nil
+	default:
+		return NewAniListSource(anilist), nil
+	}
+}
+
+// NewSourcesFromEnv builds the sources listed in METADATA_SOURCES (comma-separated, primary first),
+// falling back to DefaultSourceOrder. Unknown names are skipped with a warning.
+// In replay mode only AniList is used whatever METADATA_SOURCES says, so replay stays offline.
+func NewSourcesFromEnv(anilist AniListAPI) []MetadataSource {
+	names := DefaultSourceOrder
+	if v := os.Getenv("METADATA_SOURCES"); v != "" {
+		names = strings.Split(v, ",")
+	}
+	replay := modeFromEnv() == ModeReplay
+
+	seen := make(map[string]bool)
+	var sources []MetadataSource
+	for _, name := range names {
+		source, err := NewSourceFromName(name, anilist)
+		if err != nil {
+			log.Printf("Warning: Skipping metadata source: %v", err)
+			continue
+		}
+		if seen[source.Name()] {
+			continue
+		}
+		if replay && source.Name() != SourceAniList {
+			log.Printf("Warning: Skipping metadata source %s, ANILIST_MODE is %s and only AniList is replayed from fixtures", source.Name(), ModeReplay)
+			continue
+		}
+		seen[source.Name()] = true
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		log.Printf("Warning: No valid METADATA_SOURCES configured, using %s only", SourceAniList)
+		sources = append(sources, NewAniListSource(anilist))
+	}
+	return sources
+}
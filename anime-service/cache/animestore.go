@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnimeStore keeps every anime AniList returned in a list in the anime_caches table.
+// It is the last resort when AniList (and the fallback sources) can't be reached: lists are then
+// rebuilt from local rows, which may be outdated or incomplete.
+type AnimeStore struct {
+	db *gorm.DB
+}
+
+// NewAnimeStore creates a store on the anime_caches table
+func NewAnimeStore(db *gorm.DB) *AnimeStore {
+	return &AnimeStore{db: db}
+}
+
+// Save upserts list entries. Entries from other sources are skipped, their IDs and data may not be AniList's.
+func (s *AnimeStore) Save(ctx context.Context, animes []models.AnimeCache) error {
+	rows := make([]models.AnimeCache, 0, len(animes))
+	for _, a := range animes {
+		if a.MetadataSource == "" {
+			rows = append(rows, a)
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"title", "cover_image", "format", "total_episodes", "status", "season", "season_year", "average_score",
+			"popularity", "title_romaji", "title_english", "title_native", "synonyms", "search_titles", "is_adult", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+// Popular returns stored anime by popularity, which also stands in for trending
+func (s *AnimeStore) Popular(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return s.page(s.lists(ctx), page, perPage)
+}
+
+// ByStatus returns stored anime with an AniList status (e.g. NOT_YET_RELEASED, RELEASING) by popularity
+func (s *AnimeStore) ByStatus(ctx context.Context, status string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return s.page(s.lists(ctx).Where("status = ?", status), page, perPage)
+}
+
+// BySeason returns stored anime of a season by popularity
+func (s *AnimeStore) BySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	return s.page(s.lists(ctx).Where("season = ? AND season_year = ?", season, year), page, perPage)
+}
+
+// Search finds stored anime by any of their titles (romaji, English, native, synonyms), best matches first.
+// Full-text matches, titles containing the query and titles within trigram word similarity
+// of it (typos, missing letters) all count.
+func (s *AnimeStore) Search(ctx context.Context, query string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	filtered := s.lists(ctx).Where(
+		"to_tsvector('simple', coalesce(search_titles, '')) @@ plainto_tsquery('simple', ?) OR search_titles ILIKE ? OR ? <% search_titles",
+		query, "%"+escapeLike(query)+"%", query,
+	)
+	relevance := clause.OrderBy{Expression: clause.Expr{
+		SQL:                "ts_rank(to_tsvector('simple', coalesce(search_titles, '')), plainto_tsquery('simple', ?)) + word_similarity(?, coalesce(search_titles, '')) DESC",
+		Vars:               []interface{}{query, query},
+		WithoutParentheses: true,
+	}}
+	return s.page(filtered, page, perPage, relevance)
+}
+
+// ByIDs returns the stored anime among ids, in the order of ids
+func (s *AnimeStore) ByIDs(ctx context.Context, ids []int) ([]models.AnimeCache, error) {
+	var rows []models.AnimeCache
+	if err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.AnimeCache, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+	animes := make([]models.AnimeCache, 0, len(rows))
+	for _, id := range ids {
+		if anime, ok := byID[id]; ok {
+			animes = append(animes, anime)
+		}
+	}
+	return animes, nil
+}
+
+// lists starts a list query, leaving adult anime out unless ctx allows them (see api.WithAdultContent).
+// Rows whose flag is unknown (NULL, saved before it was recorded) are left out too, until a list save
+// or a details fetch fills it in. ByIDs doesn't use it: anime asked for by ID are returned whatever they are.
+func (s *AnimeStore) lists(ctx context.Context) *gorm.DB {
+	query := s.db.WithContext(ctx)
+	if !api.AdultContentAllowed(ctx) {
+		query = query.Where("is_adult = FALSE")
+	}
+	return query
+}
+
+// page runs a filtered query ordered by popularity and counts all matching rows.
+// orders, if any, sort before popularity.
+func (s *AnimeStore) page(query *gorm.DB, page int, perPage int, orders ...interface{}) ([]models.AnimeCache, int, error) {
+	query = query.Session(&gorm.Session{}) // Shared by the count and the select
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 20
+	}
+	var total int64
+	if err := query.Model(&models.AnimeCache{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	ordered := query
+	for _, order := range orders {
+		ordered = ordered.Order(order)
+	}
+	var rows []models.AnimeCache
+	err := ordered.Order("popularity DESC NULLS LAST").Order("id").
+		Offset((page - 1) * perPage).Limit(perPage).
+		Find(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, int(total), nil
+}
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
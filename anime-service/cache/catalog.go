@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// DefaultCatalogTTL is how long the genre and tag catalogs are kept, they rarely change
+const DefaultCatalogTTL = 24 * time.Hour
+
+// CatalogSource loads the genre and tag catalogs (implemented by api.AniListAPI)
+type CatalogSource interface {
+	GetGenres(ctx context.Context) ([]string, error)
+	GetTags(ctx context.Context) ([]models.MediaTag, error)
+}
+
+// Catalog keeps AniList's genre and tag catalogs in memory.
+// An outdated catalog keeps being served when reloading it fails.
+type Catalog struct {
+	source CatalogSource
+	ttl    time.Duration
+
+	mu              sync.Mutex
+	genres          []string
+	genresFetchedAt time.Time
+	tags            []models.MediaTag
+	tagsFetchedAt   time.Time
+}
+
+// NewCatalog creates a catalog cache in front of the given source.
+// The TTL can be tuned with ANIME_CATALOG_TTL (Go duration string, e.g. "12h").
+func NewCatalog(source CatalogSource) *Catalog {
+	return &Catalog{
+		source: source,
+		ttl:    durationFromEnv("ANIME_CATALOG_TTL", DefaultCatalogTTL),
+	}
+}
+
+// Genres returns the genre catalog
+func (c *Catalog) Genres(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.genres != nil && time.Since(c.genresFetchedAt) < c.ttl {
+		return c.genres, nil
+	}
+	genres, err := c.source.GetGenres(ctx)
+	if err != nil {
+		if c.genres != nil {
+			log.Printf("Warning: Serving outdated genre catalog after reload failed: %v", err)
+			return c.genres, nil
+		}
+		return nil, err
+	}
+	c.genres, c.genresFetchedAt = genres, time.Now()
+	return genres, nil
+}
+
+// Tags returns the tag catalog
+func (c *Catalog) Tags(ctx context.Context) ([]models.MediaTag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tags != nil && time.Since(c.tagsFetchedAt) < c.ttl {
+		return c.tags, nil
+	}
+	tags, err := c.source.GetTags(ctx)
+	if err != nil {
+		if c.tags != nil {
+			log.Printf("Warning: Serving outdated tag catalog after reload failed: %v", err)
+			return c.tags, nil
+		}
+		return nil, err
+	}
+	c.tags, c.tagsFetchedAt = tags, time.Now()
+	return tags, nil
+}
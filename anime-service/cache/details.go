@@ -0,0 +1,251 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Default TTLs per AniList media status, overridable through the environment
+	DefaultReleasingTTL = 6 * time.Hour
+	DefaultFinishedTTL  = 7 * 24 * time.Hour
+	DefaultOtherTTL     = 24 * time.Hour
+	// How long past expiry a row may still be served while it is refreshed in the background.
+	// Older rows are refetched synchronously.
+	DefaultMaxStale = 7 * 24 * time.Hour
+	// BackgroundRefreshTimeout bounds refreshes that no request is waiting for
+	BackgroundRefreshTimeout = 30 * time.Second
+	// eachBatchSize is how many rows Each reads at a time
+	eachBatchSize = 100
+)
+
+// DetailsSource is where the cache loads details from on a miss (an AniList client or a fallback chain)
+type DetailsSource interface {
+	GetAnimeByID(ctx context.Context, id int) (*models.AnimeDetails, error)
+}
+
+// AnimeDetailsCache is a read-through cache for AniList media details backed by the anime_details table
+type AnimeDetailsCache struct {
+	client       DetailsSource
+	db           *gorm.DB
+	releasingTTL time.Duration
+	finishedTTL  time.Duration
+	otherTTL     time.Duration
+	maxStale     time.Duration
+
+	mu         sync.Mutex
+	refreshing map[int]bool // IDs with a background refresh in flight
+
+	onStore []func(context.Context, *models.AnimeDetails) // Called with every freshly fetched AniList entry
+}
+
+// NewAnimeDetailsCache creates a details cache in front of the given source.
+// TTLs can be tuned with ANIME_DETAILS_TTL_RELEASING, ANIME_DETAILS_TTL_FINISHED,
+// ANIME_DETAILS_TTL_DEFAULT and ANIME_DETAILS_MAX_STALE (Go duration strings, e.g. "6h").
+func NewAnimeDetailsCache(client DetailsSource, db *gorm.DB) *AnimeDetailsCache {
+	return &AnimeDetailsCache{
+		client:       client,
+		db:           db,
+		releasingTTL: durationFromEnv("ANIME_DETAILS_TTL_RELEASING", DefaultReleasingTTL),
+		finishedTTL:  durationFromEnv("ANIME_DETAILS_TTL_FINISHED", DefaultFinishedTTL),
+		otherTTL:     durationFromEnv("ANIME_DETAILS_TTL_DEFAULT", DefaultOtherTTL),
+		maxStale:     durationFromEnv("ANIME_DETAILS_MAX_STALE", DefaultMaxStale),
+		refreshing:   make(map[int]bool),
+	}
+}
+
+// OnStore registers a hook called whenever fresh AniList details are fetched and cached
+func (c *AnimeDetailsCache) OnStore(fn func(context.Context, *models.AnimeDetails)) {
+	c.onStore = append(c.onStore, fn)
+}
+
+// TTLFor returns how long details with the given AniList status stay fresh
+func (c *AnimeDetailsCache) TTLFor(status string) time.Duration {
+	switch status {
+	case "RELEASING":
+		return c.releasingTTL
+	case "FINISHED", "CANCELLED":
+		return c.finishedTTL
+	default:
+		return c.otherTTL
+	}
+}
+
+// Get returns details for an anime, serving from the database when possible.
+// Fresh rows are returned as is. Expired rows are returned immediately and refreshed in the background,
+// unless they are older than maxStale, in which case AniList is queried synchronously.
+// stale is true when the returned details are past their TTL: either the refresh is under way,
+// or AniList couldn't be reached and an outdated row is better than nothing.
+func (c *AnimeDetailsCache) Get(ctx context.Context, id int) (details *models.AnimeDetails, stale bool, err error) {
+	now := time.Now()
+
+	var cached *models.AnimeDetails
+	var record models.AnimeDetailsRecord
+	err = c.db.WithContext(ctx).First(&record, "id = ?", id).Error
+	switch {
+	case err == nil:
+		decoded, decodeErr := record.ToAnimeDetails()
+		if decodeErr != nil {
+			log.Printf("Warning: Failed to decode cached details for anime ID %d: %v", id, decodeErr)
+			break
+		}
+		if !record.IsExpired(now) {
+			return withCountdown(decoded, now), false, nil
+		}
+		if now.Sub(record.ExpiresAt) < c.maxStale {
+			c.refreshAsync(id)
+			return withCountdown(decoded, now), true, nil
+		}
+		cached = decoded
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Cache miss, fetch below
+	default:
+		log.Printf("Warning: Failed to read details cache for anime ID %d: %v", id, err)
+	}
+
+	details, err = c.Refresh(ctx, id)
+	if err != nil {
+		// A missing anime stays missing, whatever was cached
+		if cached != nil && !errors.Is(err, api.ErrNotFound) {
+			log.Printf("Warning: Serving expired details for anime ID %d after refresh failed: %v", id, err)
+			return withCountdown(cached, now), true, nil
+		}
+		return nil, false, err
+	}
+	return details, false, nil
+}
+
+// Refresh fetches details from the source and stores them, regardless of the current cache state
+func (c *AnimeDetailsCache) Refresh(ctx context.Context, id int) (*models.AnimeDetails, error) {
+	details, err := c.client.GetAnimeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Data from a fallback source is served but not cached, so AniList data replaces it as soon as possible
+	if details.MetadataSource != "" {
+		return details, nil
+	}
+	if err := c.Store(ctx, details); err != nil {
+		// The data is still good, only the cache write failed
+		log.Printf("Warning: Failed to store details for anime ID %d in cache: %v", id, err)
+	}
+	for _, fn := range c.onStore {
+		fn(ctx, details)
+	}
+	return details, nil
+}
+
+// Warm refreshes an anime's details when they are missing from the cache or expired.
+// It reports whether the source was queried.
+func (c *AnimeDetailsCache) Warm(ctx context.Context, id int) (bool, error) {
+	var record models.AnimeDetailsRecord
+	err := c.db.WithContext(ctx).Select("id", "expires_at").First(&record, "id = ?", id).Error
+	switch {
+	case err == nil && !record.IsExpired(time.Now()):
+		return false, nil
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		return false, err
+	}
+	_, err = c.Refresh(ctx, id)
+	return true, err
+}
+
+// Each calls fn with the cached details of every anime, expired ones included, without querying the source.
+// expired tells whether the details are past their TTL. It stops at the first error fn returns.
+func (c *AnimeDetailsCache) Each(ctx context.Context, fn func(details *models.AnimeDetails, expired bool) error) error {
+	var records []models.AnimeDetailsRecord
+	return c.db.WithContext(ctx).Select("id", "payload", "expires_at").FindInBatches(&records, eachBatchSize, func(tx *gorm.DB, batch int) error {
+		now := time.Now()
+		for i := range records {
+			details, err := records[i].ToAnimeDetails()
+			if err != nil {
+				log.Printf("Warning: Failed to decode cached details for anime ID %d: %v", records[i].ID, err)
+				continue
+			}
+			if err := fn(details, records[i].IsExpired(now)); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	}).Error
+}
+
+// Store upserts details into the cache with a TTL based on their status.
+// Entries of releasing anime expire no later than their next episode airs.
+func (c *AnimeDetailsCache) Store(ctx context.Context, details *models.AnimeDetails) error {
+	now := time.Now()
+	expiresAt := now.Add(c.TTLFor(details.Status))
+	if next := details.NextAiringEpisode; next != nil {
+		if airsAt := time.Unix(next.AiringAt, 0); airsAt.After(now) && airsAt.Before(expiresAt) {
+			expiresAt = airsAt
+		}
+	}
+	record, err := models.NewAnimeDetailsRecord(details, now, expiresAt)
+	if err != nil {
+		return err
+	}
+	return c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"title_romaji", "title_english", "title_native", "format", "status", "season", "season_year",
+			"episodes", "duration", "cover_image_large", "cover_image_medium", "banner_image",
+			"average_score", "popularity", "country_of_origin", "source", "mean_score", "favourites", "is_adult", "payload", "fetched_at", "expires_at", "updated_at",
+		}),
+	}).Create(record).Error
+}
+
+// withCountdown recomputes the next episode countdown of cached details, which was relative to their fetch time
+func withCountdown(details *models.AnimeDetails, now time.Time) *models.AnimeDetails {
+	if details.NextAiringEpisode != nil {
+		details.NextAiringEpisode.UpdateTimeUntilAiring(now)
+	}
+	return details
+}
+
+// refreshAsync refreshes an entry in the background, at most once at a time per ID.
+// The refresh outlives the request that triggered it, so it gets its own deadline.
+func (c *AnimeDetailsCache) refreshAsync(id int) {
+	c.mu.Lock()
+	if c.refreshing[id] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[id] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, id)
+			c.mu.Unlock()
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), BackgroundRefreshTimeout)
+		defer cancel()
+		if _, err := c.Refresh(ctx, id); err != nil {
+			log.Printf("Warning: Background refresh of anime ID %d failed: %v", id, err)
+		}
+	}()
+}
+
+// durationFromEnv parses a Go duration from an environment variable, falling back on error
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: Invalid duration %q for %s, using default %s", value, key, fallback)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DiscoveryStore keeps prefetched discovery lists in the discovery_lists table
+type DiscoveryStore struct {
+	db *gorm.DB
+}
+
+// NewDiscoveryStore creates a store on the discovery_lists table
+func NewDiscoveryStore(db *gorm.DB) *DiscoveryStore {
+	return &DiscoveryStore{db: db}
+}
+
+// Save replaces the prefetched part of a list
+func (s *DiscoveryStore) Save(ctx context.Context, name string, total int, entries []models.AnimeCache) error {
+	row, err := models.NewDiscoveryList(name, total, entries, time.Now())
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"total", "entries", "warmed_at", "updated_at"}),
+	}).Create(row).Error
+}
+
+// Page returns a page of a prefetched list. ok is false when the list wasn't warmed within maxAge
+// or when the page goes past the prefetched entries, the caller should then ask AniList.
+func (s *DiscoveryStore) Page(ctx context.Context, name string, page int, perPage int, maxAge time.Duration) (results []models.AnimeCache, total int, ok bool, err error) {
+	if page < 1 || perPage < 1 {
+		return nil, 0, false, nil
+	}
+	var row models.DiscoveryList
+	if err := s.db.WithContext(ctx).First(&row, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, false, nil
+		}
+		return nil, 0, false, err
+	}
+	if time.Since(row.WarmedAt) > maxAge {
+		return nil, 0, false, nil
+	}
+	entries, err := row.ToAnimeCaches()
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	start, end := (page-1)*perPage, page*perPage
+	// Entries past the prefetched ones exist upstream, unless the whole list was prefetched
+	if end > len(entries) && len(entries) < row.Total {
+		return nil, 0, false, nil
+	}
+	if start > len(entries) {
+		start = len(entries)
+	}
+	if end > len(entries) {
+		end = len(entries)
+	}
+	return entries[start:end], row.Total, true, nil
+}
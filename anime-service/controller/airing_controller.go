@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+)
+
+const (
+	// defaultAiringWindow is the window served when ?to= is omitted (one calendar week)
+	defaultAiringWindow = 7 * 24 * time.Hour
+	// maxAiringWindow bounds ?from= to ?to= so a single request can't page through a whole year
+	maxAiringWindow = 31 * 24 * time.Hour
+)
+
+// GetAiringSchedule returns the episodes airing between ?from= and ?to= (Unix seconds or RFC 3339).
+// from defaults to now and to defaults to one week after from.
+func GetAiringSchedule(c *gin.Context) {
+	from := time.Now()
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := parseAiringTime(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from: " + err.Error()})
+			return
+		}
+		from = parsed
+	}
+	to := from.Add(defaultAiringWindow)
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := parseAiringTime(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to: " + err.Error()})
+			return
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+	if to.Sub(from) > maxAiringWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Time window must not exceed %d days", int(maxAiringWindow.Hours()/24))})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "50"))
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+	perPage = min(perPage, api.MaxPerPage)
+	results, total, err := anilistClient.GetAiringSchedule(c.Request.Context(), from.Unix(), to.Unix(), page, perPage)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch airing schedule")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total, "from": from.Unix(), "to": to.Unix()}})
+}
+
+// parseAiringTime accepts a Unix timestamp in seconds or an RFC 3339 date
+func parseAiringTime(raw string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a Unix timestamp or an RFC 3339 date, got %q", raw)
+	}
+	return t, nil
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -8,14 +10,56 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/cache"
 	"github.com/vrstep/wawatch-backend/config"
+	"github.com/vrstep/wawatch-backend/description"
+	"github.com/vrstep/wawatch-backend/franchise"
 	"github.com/vrstep/wawatch-backend/models"
+	"github.com/vrstep/wawatch-backend/recommend"
+	"github.com/vrstep/wawatch-backend/warmer"
 )
 
 var anilistClient api.AniListAPI
 
+// metadataSources wraps anilistClient with the fallback sources configured in METADATA_SOURCES
+var metadataSources *api.FallbackSource
+
+// catalog caches AniList's genre and tag catalogs
+var catalog *cache.Catalog
+
+// detailsCache serves GetAnimeDetails from the anime_details table; nil until InitDetailsCache is called
+var detailsCache *cache.AnimeDetailsCache
+
 func SetAniListClient(client api.AniListAPI) {
 	anilistClient = client
+	metadataSources = api.NewFallbackSource(api.NewSourcesFromEnv(client))
+	catalog = cache.NewCatalog(client)
+}
+
+// InitDetailsCache sets up the anime details cache and the local anime store. Must be called after config.ConnectDB().
+func InitDetailsCache() {
+	detailsCache = cache.NewAnimeDetailsCache(metadataSources, config.DB)
+	animeStore = cache.NewAnimeStore(config.DB)
+}
+
+// getAnimeDetails reads through the details cache when it is configured.
+// stale is true when outdated cached details were served (see AnimeDetailsCache.Get).
+func getAnimeDetails(ctx context.Context, animeID int) (*models.AnimeDetails, bool, error) {
+	if detailsCache == nil {
+		details, err := metadataSources.GetAnimeByID(ctx, animeID)
+		return details, false, err
+	}
+	return detailsCache.Get(ctx, animeID)
+}
+
+// sourceForRequest returns the metadata source picked with ?source= (e.g. "mal", "kitsu"),
+// or the fallback chain when none is given
+func sourceForRequest(c *gin.Context) (api.MetadataSource, error) {
+	name := c.Query("source")
+	if name == "" {
+		return metadataSources, nil
+	}
+	return metadataSources.Source(name)
 }
 
 func init() {
@@ -25,29 +69,58 @@ func init() {
 	SetAniListClient(api.NewAniListClient())
 }
 
-// SearchAnime handles anime search requests
+// SearchAnime handles anime search requests. Titles are searched in anime_caches instead of AniList
+// with ?local=true, and when AniList can't be reached.
 func SearchAnime(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
 		return
 	}
+	source, err := sourceForRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+	perPage = min(perPage, api.MaxPerPage)
 
-	results, total, err := anilistClient.SearchAnime(query, page, perPage)
+	// ?local=true searches the anime seen so far without calling AniList, typos included
+	if c.Query("local") == "true" {
+		if animeStore == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Local search is not available"})
+			return
+		}
+		results, total, err := animeStore.Search(c.Request.Context(), query, page, perPage)
+		if err != nil {
+			respondError(c, err, "Not found", "Failed to search anime")
+			return
+		}
+		respondPage(c, results, total, page, perPage, false)
+		return
+	}
+
+	results, total, stale, err := fetchList(c,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return source.SearchAnime(ctx, query, page, perPage)
+		},
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return animeStore.Search(ctx, query, page, perPage)
+		})
 	if err != nil {
-		log.Printf("Error searching anime (query: %s): %v", query, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search anime"})
+		respondError(c, err, "Not found", "Failed to search anime")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"data": results,
-		"meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total},
-	})
+	respondPage(c, results, total, page, perPage, stale)
 }
 
-// GetAnimeDetails fetches detailed information about an anime and its watch providers
+// GetAnimeDetails fetches detailed information about an anime and its watch providers.
+// The description is sanitized into ?descriptionFormat= (html by default, plain or markdown).
 func GetAnimeDetails(c *gin.Context) {
 	idParam := c.Param("id")
 	animeID, err := strconv.Atoi(idParam)
@@ -55,16 +128,24 @@ func GetAnimeDetails(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID format"})
 		return
 	}
+	format := c.DefaultQuery("descriptionFormat", description.FormatHTML)
+	if !description.IsFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid descriptionFormat. Use plain, markdown or html"})
+		return
+	}
 
-	// Get detailed info from AniList
-	animeDetails, err := anilistClient.GetAnimeByID(animeID)
-	if err != nil {
-		if strings.Contains(err.Error(), "no anime data returned") || strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Anime not found on AniList"})
-		} else {
-			log.Printf("Error fetching anime details from AniList (ID: %d): %v", animeID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch anime details from AniList"})
+	// IDs from other databases (?source=mal) are served straight from that source, without caching or providers
+	if name := c.Query("source"); name != "" {
+		if normalized, _ := api.NormalizeSourceName(name); normalized != api.SourceAniList {
+			getForeignAnimeDetails(c, animeID, format)
+			return
 		}
+	}
+
+	// Get detailed info from the details cache (falls through to AniList on a miss)
+	animeDetails, stale, err := getAnimeDetails(c.Request.Context(), animeID)
+	if err != nil {
+		respondError(c, err, "Anime not found on AniList", "Failed to fetch anime details from AniList")
 		return
 	}
 
@@ -83,78 +164,297 @@ func GetAnimeDetails(c *gin.Context) {
 		providers = []models.WatchProvider{}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"anime":     animeDetails,
+	c.JSON(http.StatusOK, markStale(c, gin.H{
+		"anime":     description.Apply(animeDetails, format),
 		"providers": providers,
+	}, stale))
+}
+
+// staffLanguages are the voice actor languages AniList supports (StaffLanguage enum)
+var staffLanguages = map[string]bool{
+	"JAPANESE": true, "ENGLISH": true, "KOREAN": true, "ITALIAN": true, "SPANISH": true,
+	"PORTUGUESE": true, "FRENCH": true, "GERMAN": true, "HEBREW": true, "HUNGARIAN": true,
+}
+
+// MaxAnimeBatchSize bounds the number of IDs accepted by GetAnimeBatch
+const MaxAnimeBatchSize = 500
+
+// GetAnimeBatch returns list entries for many anime at once (?ids=1,2,3), in the requested order.
+// IDs AniList doesn't know are listed under "missing".
+// While AniList is unreachable, entries come from anime_caches and IDs never stored there are missing too.
+func GetAnimeBatch(c *gin.Context) {
+	ids, err := parseIDList(c.Query("ids"))
+	if err != nil || len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a comma-separated list of integers"})
+		return
+	}
+	if len(ids) > MaxAnimeBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many ids, maximum is " + strconv.Itoa(MaxAnimeBatchSize)})
+		return
+	}
+
+	stale := false
+	results, err := anilistClient.GetAnimeByIDs(c.Request.Context(), ids)
+	if err == nil {
+		rememberAnime(results)
+	} else if animeStore != nil && upstreamDown(err) {
+		var localErr error
+		if results, localErr = animeStore.ByIDs(c.Request.Context(), ids); localErr == nil {
+			err, stale = nil, true
+		}
+	}
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch anime batch")
+		return
+	}
+	returned := make(map[int]bool, len(results))
+	for _, anime := range results {
+		returned[anime.ID] = true
+	}
+	missing := []int{}
+	for _, id := range ids {
+		if !returned[id] {
+			missing = append(missing, id)
+		}
+	}
+	c.JSON(http.StatusOK, markStale(c, gin.H{"data": results, "missing": missing}, stale))
+}
+
+// GetAnimeCharacters fetches a page of an anime's characters with their voice actors grouped by language
+func GetAnimeCharacters(c *gin.Context) {
+	animeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID format"})
+		return
+	}
+	language := strings.ToUpper(c.Query("language"))
+	if language != "" && !staffLanguages[language] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language, use e.g. JAPANESE or ENGLISH"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "25"))
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+	perPage = min(perPage, api.MaxPerPage)
+
+	results, total, err := anilistClient.GetAnimeCharacters(c.Request.Context(), animeID, language, page, perPage)
+	if err != nil {
+		respondError(c, err, "Anime not found on AniList", "Failed to fetch characters")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+}
+
+// GetAnimeRelations returns an anime's direct relations (sequels, prequels, side stories, adaptations...)
+func GetAnimeRelations(c *gin.Context) {
+	animeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID format"})
+		return
+	}
+
+	anime, relations, err := anilistClient.GetAnimeRelations(c.Request.Context(), animeID)
+	if err != nil {
+		respondError(c, err, "Anime not found on AniList", "Failed to fetch relations")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"anime": anime, "data": relations})
+}
+
+// GetAnimeFranchise walks the relation graph around an anime (?depth=, default 3) and returns a suggested watch order
+func GetAnimeFranchise(c *gin.Context) {
+	animeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID format"})
+		return
+	}
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(franchise.DefaultMaxDepth)))
+	if err != nil || depth < 1 || depth > franchise.MaxDepthLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("depth must be between 1 and %d", franchise.MaxDepthLimit)})
+		return
+	}
+
+	result, err := franchise.Build(c.Request.Context(), anilistClient, animeID, depth)
+	if err != nil {
+		respondError(c, err, "Anime not found on AniList", "Failed to build franchise")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// getForeignAnimeDetails serves details for an ID in a non-AniList source's ID space
+func getForeignAnimeDetails(c *gin.Context, id int, format string) {
+	source, err := sourceForRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	animeDetails, err := source.GetAnimeByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Anime not found on "+source.Name(), "Failed to fetch anime details from "+source.Name())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"anime":     description.Apply(animeDetails, format),
+		"providers": []models.WatchProvider{},
 	})
 }
 
-// GetPopularAnime fetches popular anime from AniList
+// GetPopularAnime fetches popular anime, from the cache warmer's copy when it has the page
 func GetPopularAnime(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
-	results, total, err := anilistClient.GetPopularAnime(page, perPage)
+	source, err := sourceForRequest(c)
 	if err != nil {
-		log.Printf("Error fetching popular anime: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch popular anime"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+	results, total, stale, err := fetchDiscoveryList(c, warmer.ListPopular, page, perPage,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return source.GetPopularAnime(ctx, page, perPage)
+		},
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return animeStore.Popular(ctx, page, perPage)
+		})
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch popular anime")
+		return
+	}
+	respondPage(c, results, total, page, perPage, stale)
 }
 
-// GetTrendingAnime fetches trending anime from AniList
+// GetTrendingAnime fetches trending anime, from the cache warmer's copy when it has the page
 func GetTrendingAnime(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
-	results, total, err := anilistClient.GetTrendingAnime(page, perPage)
+	source, err := sourceForRequest(c)
 	if err != nil {
-		log.Printf("Error fetching trending anime: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trending anime"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+	results, total, stale, err := fetchDiscoveryList(c, warmer.ListTrending, page, perPage,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return source.GetTrendingAnime(ctx, page, perPage)
+		},
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return animeStore.Popular(ctx, page, perPage)
+		})
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch trending anime")
+		return
+	}
+	respondPage(c, results, total, page, perPage, stale)
 }
 
-// GetAnimeRecommendations fetches recommendations (placeholder)
+// GetAnimeRecommendations ranks the anime recommended to fans of several seed anime (?ids=1,2,3),
+// e.g. the ones a user rated best. Anime recommended by more seeds come first.
 func GetAnimeRecommendations(c *gin.Context) {
+	seeds, err := parseIDList(c.Query("ids"))
+	if err != nil || len(seeds) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a comma-separated list of seed anime IDs"})
+		return
+	}
+	if len(seeds) > recommend.MaxSeeds {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many ids, maximum is " + strconv.Itoa(recommend.MaxSeeds)})
+		return
+	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "10"))
-	results, total, err := anilistClient.GetPopularAnime(page, perPage) // Placeholder
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+
+	ranked, err := recommend.ForSeeds(c.Request.Context(), anilistClient, seeds)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch recommendations")
+		return
+	}
+	total := len(ranked)
+	start, end := min((page-1)*perPage, total), min(page*perPage, total)
+	results := ranked[start:end]
+	rememberRecommendations(results)
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": end < total}})
+}
+
+// GetRecommendationsForAnime fetches a page of the anime AniList users recommend to fans of an anime, best rated first
+func GetRecommendationsForAnime(c *gin.Context) {
+	animeID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		log.Printf("Error fetching recommendations (placeholder): %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recommendations"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID format"})
 		return
 	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "10"))
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+	perPage = min(perPage, api.MaxPerPage)
+
+	results, total, err := anilistClient.GetAnimeRecommendations(c.Request.Context(), animeID, page, perPage)
+	if err != nil {
+		respondError(c, err, "Anime not found on AniList", "Failed to fetch recommendations")
+		return
+	}
+	rememberRecommendations(results)
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
 }
 
-// GetUpcomingAnime fetches upcoming anime from AniList
+// rememberRecommendations keeps recommended anime in anime_caches, like list entries
+func rememberRecommendations(recommendations []models.Recommendation) {
+	animes := make([]models.AnimeCache, len(recommendations))
+	for i, r := range recommendations {
+		animes[i] = r.Anime
+	}
+	rememberAnime(animes)
+}
+
+// GetUpcomingAnime fetches upcoming anime, from the cache warmer's copy when it has the page
 func GetUpcomingAnime(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
-	results, total, err := anilistClient.GetUpcomingAnime(page, perPage)
+	source, err := sourceForRequest(c)
 	if err != nil {
-		log.Printf("Error fetching upcoming anime: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upcoming anime"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+	results, total, stale, err := fetchDiscoveryList(c, warmer.ListUpcoming, page, perPage,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return source.GetUpcomingAnime(ctx, page, perPage)
+		},
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return animeStore.ByStatus(ctx, "NOT_YET_RELEASED", page, perPage)
+		})
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch upcoming anime")
+		return
+	}
+	respondPage(c, results, total, page, perPage, stale)
 }
 
 // GetRecentlyReleasedAnime fetches recently released anime
 func GetRecentlyReleasedAnime(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
-	results, total, err := anilistClient.GetRecentlyReleasedAnime(page, perPage)
+	results, total, stale, err := fetchList(c,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return anilistClient.GetRecentlyReleasedAnime(ctx, page, perPage)
+		},
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return animeStore.ByStatus(ctx, "RELEASING", page, perPage)
+		})
 	if err != nil {
-		log.Printf("Error fetching recently released anime: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recently released anime"})
+		respondError(c, err, "Not found", "Failed to fetch recently released anime")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+	respondPage(c, results, total, page, perPage, stale)
 }
 
-// GetAnimeBySeason fetches anime by season
+// GetAnimeBySeason fetches anime by season, from the cache warmer's copy for the current season
 func GetAnimeBySeason(c *gin.Context) {
 	yearParam := c.Param("year")
 	seasonParam := strings.ToUpper(c.Param("season"))
@@ -168,18 +468,29 @@ func GetAnimeBySeason(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season. Use WINTER, SPRING, SUMMER, or FALL"})
 		return
 	}
+	source, err := sourceForRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
-	results, total, err := anilistClient.GetAnimeBySeason(year, seasonParam, page, perPage)
+	results, total, stale, err := fetchDiscoveryList(c, warmer.SeasonList(year, seasonParam), page, perPage,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return source.GetAnimeBySeason(ctx, year, seasonParam, page, perPage)
+		},
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return animeStore.BySeason(ctx, year, seasonParam, page, perPage)
+		})
 	if err != nil {
-		log.Printf("Error fetching anime by season (Year: %d, Season: %s): %v", year, seasonParam, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch anime by season"})
+		respondError(c, err, "Not found", "Failed to fetch anime by season")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+	respondPage(c, results, total, page, perPage, stale)
 }
 
-// ExploreAnime fetches anime by a comma-separated list of tags
+// ExploreAnime fetches anime by a comma-separated list of AniList tags (e.g. "Time Skip,Iyashikei").
+// excludeTags removes anime having any of the given tags, minTagRank (0-100) sets how relevant the tags must be.
 func ExploreAnime(c *gin.Context) {
 	tagsQuery := c.Query("tags")
 	if tagsQuery == "" {
@@ -191,14 +502,27 @@ func ExploreAnime(c *gin.Context) {
 		tags[i] = strings.TrimSpace(tag)
 	}
 
+	var minTagRank *int // nil uses api.DefaultMinTagRank, 0 is a valid threshold
+	if raw := c.Query("minTagRank"); raw != "" {
+		rank, err := strconv.Atoi(raw)
+		if err != nil || rank < 0 || rank > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "minTagRank must be an integer between 0 and 100"})
+			return
+		}
+		minTagRank = &rank
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 
-	results, total, err := anilistClient.GetAnimeByTags(tags, page, perPage)
+	results, total, stale, err := fetchList(c,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return anilistClient.GetAnimeByTags(ctx, tags, parseListParam(c.Query("excludeTags")), minTagRank, page, perPage)
+		},
+		nil)
 	if err != nil {
-		log.Printf("Error fetching anime by tags (%v): %v", tags, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch anime by tags"})
+		respondError(c, err, "Not found", "Failed to fetch anime by tags")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+	respondPage(c, results, total, page, perPage, stale)
 }
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// BrowseAnime searches anime with any combination of filters:
+//   - q: search text
+//   - genres, excludeGenres, tags, excludeTags, formats, status: comma-separated lists
+//   - minTagRank: how relevant tags must be (0-100, default 60)
+//   - season, yearFrom, yearTo (or year for both), scoreMin, scoreMax, episodesMin, episodesMax
+//   - sort: popularity (default), trending, score, favourites, newest, oldest, title, episodes, relevance
+func BrowseAnime(c *gin.Context) {
+	filter := api.BrowseFilter{
+		Search:         strings.TrimSpace(c.Query("q")),
+		Genres:         parseListParam(c.Query("genres")),
+		ExcludedGenres: parseListParam(c.Query("excludeGenres")),
+		Tags:           parseListParam(c.Query("tags")),
+		ExcludedTags:   parseListParam(c.Query("excludeTags")),
+		Formats:        parseListParam(c.Query("formats")),
+		Statuses:       parseListParam(c.Query("status")),
+		Season:         c.Query("season"),
+		Sort:           c.Query("sort"),
+	}
+	ints := []struct {
+		name string
+		dest **int
+	}{
+		{"minTagRank", &filter.MinTagRank},
+		{"yearFrom", &filter.YearFrom}, {"yearTo", &filter.YearTo},
+		{"scoreMin", &filter.ScoreMin}, {"scoreMax", &filter.ScoreMax},
+		{"episodesMin", &filter.EpisodesMin}, {"episodesMax", &filter.EpisodesMax},
+	}
+	for _, p := range ints {
+		value, err := parseIntParam(c, p.name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		*p.dest = value
+	}
+	if c.Query("year") != "" {
+		if filter.YearFrom != nil || filter.YearTo != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Use either year or yearFrom/yearTo"})
+			return
+		}
+		year, err := parseIntParam(c, "year")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.YearFrom, filter.YearTo = year, year
+	}
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+	perPage = min(perPage, api.MaxPerPage)
+	// Too many filters to rebuild locally, results are only remembered for the other lists
+	results, total, stale, err := fetchList(c,
+		func(ctx context.Context) ([]models.AnimeCache, int, error) {
+			return anilistClient.BrowseAnime(ctx, filter, page, perPage)
+		},
+		nil)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to browse anime")
+		return
+	}
+	respondPage(c, results, total, page, perPage, stale)
+}
+
+// parseListParam splits a comma-separated query parameter, dropping empty values
+func parseListParam(raw string) []string {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseIntParam reads an optional integer query parameter, nil when absent
+func parseIntParam(c *gin.Context, name string) (*int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+	return &value, nil
+}
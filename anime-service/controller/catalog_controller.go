@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// GetGenres returns the genres usable in /anime/browse?genres=
+func GetGenres(c *gin.Context) {
+	genres, err := catalog.Genres(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch genres")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": genres})
+}
+
+// GetTags returns the tags usable in /anime/browse?tags= and /anime/explore, with their categories.
+// ?category= keeps a single category, ?spoilers=false and ?adult=false drop spoiler and adult tags.
+func GetTags(c *gin.Context) {
+	tags, err := catalog.Tags(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch tags")
+		return
+	}
+
+	category := c.Query("category")
+	hideSpoilers := c.Query("spoilers") == "false"
+	hideAdult := c.Query("adult") == "false"
+
+	filtered := make([]models.MediaTag, 0, len(tags))
+	categorySet := make(map[string]bool)
+	for _, t := range tags {
+		if (hideSpoilers && t.IsGeneralSpoiler) || (hideAdult && t.IsAdult) {
+			continue
+		}
+		categorySet[t.Category] = true
+		if category != "" && !strings.EqualFold(t.Category, category) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	categories := make([]string, 0, len(categorySet))
+	for name := range categorySet {
+		categories = append(categories, name)
+	}
+	sort.Strings(categories)
+
+	c.JSON(http.StatusOK, gin.H{"data": filtered, "categories": categories})
+}
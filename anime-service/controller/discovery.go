@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/cache"
+	"github.com/vrstep/wawatch-backend/config"
+	"github.com/vrstep/wawatch-backend/models"
+	"github.com/vrstep/wawatch-backend/warmer"
+)
+
+// discoveryLists holds the lists prefetched by cacheWarmer; both are nil until InitCacheWarmer is called
+var (
+	discoveryLists *cache.DiscoveryStore
+	cacheWarmer    *warmer.Warmer
+)
+
+// InitCacheWarmer starts prefetching the discovery lists in the background.
+// Must be called after InitDetailsCache.
+func InitCacheWarmer() {
+	discoveryLists = cache.NewDiscoveryStore(config.DB)
+	cacheWarmer = warmer.New(anilistClient, discoveryLists, animeStore, detailsCache)
+	cacheWarmer.Start(context.Background())
+}
+
+// fetchDiscoveryList serves a page of a discovery list from the warmer's copy when it is recent enough,
+// from AniList otherwise. When AniList can't be reached, an outdated copy is served (stale), or else local.
+// The warmer's copies leave adult anime out, requests including them always go to AniList.
+func fetchDiscoveryList(c *gin.Context, name string, page int, perPage int, fetch listFetcher, local listFetcher) ([]models.AnimeCache, int, bool, error) {
+	if discoveryLists == nil || cacheWarmer == nil || !cacheWarmer.Enabled() || c.Query("source") != "" ||
+		api.AdultContentAllowed(c.Request.Context()) {
+		return fetchList(c, fetch, local)
+	}
+	results, total, ok, err := discoveryLists.Page(c.Request.Context(), name, page, perPage, cacheWarmer.MaxAge())
+	if err != nil {
+		log.Printf("Warning: Failed to read prefetched list %s: %v", name, err)
+	}
+	if ok {
+		return results, total, false, nil
+	}
+
+	return fetchList(c, fetch, func(ctx context.Context) ([]models.AnimeCache, int, error) {
+		results, total, ok, err := discoveryLists.Page(ctx, name, page, perPage, time.Duration(math.MaxInt64))
+		if err == nil && ok {
+			return results, total, nil
+		}
+		return local(ctx)
+	})
+}
+
+// GetWarmerStatus reports what the last cache warmer run did
+func GetWarmerStatus(c *gin.Context) {
+	if cacheWarmer == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Cache warmer is not running"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": cacheWarmer.Status()})
+}
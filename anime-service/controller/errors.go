@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+)
+
+// errorStatus maps an error kind from the api package to an HTTP status
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, api.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, api.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, api.ErrUpstreamUnavailable), errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError writes the error response matching err's kind.
+// notFoundMsg is used for ErrNotFound, failureMsg for upstream and unexpected failures, which are logged.
+// Invalid input errors are returned as is since they explain what to fix.
+func respondError(c *gin.Context, err error, notFoundMsg string, failureMsg string) {
+	status := errorStatus(err)
+	switch status {
+	case http.StatusNotFound:
+		c.JSON(status, gin.H{"error": notFoundMsg})
+	case http.StatusBadRequest:
+		c.JSON(status, gin.H{"error": err.Error()})
+	case http.StatusTooManyRequests:
+		var upstreamErr *api.UpstreamError
+		if errors.As(err, &upstreamErr) && upstreamErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(upstreamErr.RetryAfter.Seconds()))))
+		}
+		c.JSON(status, gin.H{"error": "Upstream rate limit reached, try again later"})
+	default:
+		log.Printf("Error handling %s %s: %v", c.Request.Method, c.Request.URL.RequestURI(), err)
+		c.JSON(status, gin.H{"error": failureMsg})
+	}
+}
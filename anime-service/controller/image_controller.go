@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/images"
+	"github.com/vrstep/wawatch-backend/middleware"
+)
+
+// imageStore keeps proxied cover and banner images on disk; nil until InitImageProxy is called
+var imageStore *images.Store
+
+// InitImageProxy sets up the image store. Must be called after InitDetailsCache.
+func InitImageProxy() {
+	imageStore = images.NewStore()
+}
+
+// GetAnimeImage serves an anime's cover or banner from the local image store.
+// ?w= picks a width, rounded up to one of images.Widths; without it the source image is served.
+// The source URL is looked up in the details cache, so a new AniList URL is picked up with the details,
+// and the previously downloaded image keeps being served when AniList can't be reached.
+func GetAnimeImage(c *gin.Context) {
+	if imageStore == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Image proxy is not configured"})
+		return
+	}
+	animeID, err := strconv.Atoi(c.Param("animeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID format"})
+		return
+	}
+	kind := c.Param("kind")
+	if kind != images.KindCover && kind != images.KindBanner {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image kind. Use cover or banner"})
+		return
+	}
+	width := 0
+	if w := c.Query("w"); w != "" {
+		width, err = strconv.Atoi(w)
+		if err != nil || width <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid width, must be a positive integer"})
+			return
+		}
+	}
+
+	sourceURL := ""
+	details, _, detailsErr := getAnimeDetails(c.Request.Context(), animeID)
+	switch {
+	case errors.Is(detailsErr, api.ErrNotFound):
+		respondError(c, detailsErr, "Anime not found", "Failed to fetch image")
+		return
+	case detailsErr == nil && kind == images.KindCover:
+		sourceURL = details.CoverImage.Large
+		if sourceURL == "" {
+			sourceURL = details.CoverImage.Medium
+		}
+	case detailsErr == nil:
+		sourceURL = details.BannerImage
+	}
+
+	img, err := imageStore.Get(c.Request.Context(), animeID, kind, sourceURL, images.SnapWidth(width))
+	if err != nil {
+		// Without details nor a stored copy, the details error explains the failure best
+		if detailsErr != nil && errors.Is(err, images.ErrNoImage) {
+			err = detailsErr
+		}
+		respondError(c, err, "No "+kind+" image for this anime", "Failed to fetch image")
+		return
+	}
+	f, err := os.Open(img.Path)
+	if err != nil {
+		respondError(c, err, "No "+kind+" image for this anime", "Failed to fetch image")
+		return
+	}
+	defer f.Close()
+
+	c.Header("Cache-Control", middleware.CacheImages)
+	c.Header("ETag", img.ETag)
+	c.Header("Content-Type", img.ContentType)
+	// Answers If-None-Match with 304 and supports range requests
+	http.ServeContent(c.Writer, c.Request, "", img.ModTime, f)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/config"
+	"github.com/vrstep/wawatch-backend/mapping"
+)
+
+// MaxMappingBatchSize limits how many IDs a single batch lookup may ask for
+const MaxMappingBatchSize = 500
+
+var idMappings *mapping.Service
+
+// InitIDMappings sets up the cross-database ID mapping service. Must be called after config.ConnectDB().
+// If ID_MAPPING_FILE is set, that offline dataset is imported in the background on startup.
+func InitIDMappings() {
+	idMappings = mapping.NewService(config.DB, anilistClient)
+	metadataSources.SetIDResolver(idMappings) // Lets details and lists fall back to Jikan/Kitsu through translated IDs
+	if detailsCache != nil {
+		detailsCache.OnStore(idMappings.RecordDetails) // Learn AniList <-> MAL from every details fetch
+	}
+
+	if path := os.Getenv("ID_MAPPING_FILE"); path != "" {
+		go func() {
+			count, err := idMappings.ImportFile(context.Background(), path)
+			if err != nil {
+				log.Printf("Warning: Failed to import ID mapping file %s: %v", path, err)
+				return
+			}
+			log.Printf("Imported %d ID mappings from %s", count, path)
+		}()
+	}
+}
+
+// GetIDMapping resolves one ID from any supported database, e.g. /anime/map?source=mal&id=5114
+func GetIDMapping(c *gin.Context) {
+	source := c.DefaultQuery("source", mapping.SourceMAL)
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing id"})
+		return
+	}
+
+	m, err := idMappings.Lookup(c.Request.Context(), source, id)
+	if err != nil {
+		respondError(c, err, err.Error(), "Failed to resolve ID mapping")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+// GetIDMappingsBatch resolves many IDs at once, e.g. /anime/map/batch?source=mal&ids=1,5,6
+func GetIDMappingsBatch(c *gin.Context) {
+	source := c.DefaultQuery("source", mapping.SourceMAL)
+	ids, err := parseIDList(c.Query("ids"))
+	if err != nil || len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a comma-separated list of integers"})
+		return
+	}
+	if len(ids) > MaxMappingBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many ids, maximum is " + strconv.Itoa(MaxMappingBatchSize)})
+		return
+	}
+
+	found, err := idMappings.LookupMany(c.Request.Context(), source, ids)
+	if err != nil {
+		respondError(c, err, err.Error(), "Failed to resolve ID mappings")
+		return
+	}
+
+	data := make(map[string]interface{}, len(found))
+	missing := []int{}
+	for _, id := range ids {
+		if m, ok := found[id]; ok {
+			data[strconv.Itoa(id)] = m
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data, "missing": missing})
+}
+
+// ImportIDMappings loads an offline mapping dataset (Fribb/anime-lists JSON format) from the request body
+// TODO: Add admin authorization check if needed
+func ImportIDMappings(c *gin.Context) {
+	count, err := idMappings.Import(c.Request.Context(), c.Request.Body)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to import ID mappings")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "ID mappings imported", "imported": count})
+}
+
+// parseIDList parses a comma-separated list of integer IDs, dropping duplicates
+func parseIDList(raw string) ([]int, error) {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
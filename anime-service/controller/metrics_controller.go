@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+)
+
+// GetAniListStats reports how many AniList queries were requested and how many were saved by coalescing,
+// along with the state of the circuit breaker
+func GetAniListStats(c *gin.Context) {
+	reporter, ok := anilistClient.(api.StatsReporter)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "AniList client does not report statistics"})
+		return
+	}
+	stats := reporter.Stats()
+	savedRatio := 0.0
+	if stats.Requests > 0 {
+		savedRatio = float64(stats.Coalesced) / float64(stats.Requests)
+	}
+	response := gin.H{"data": stats, "savedRatio": savedRatio}
+	if breaker, ok := anilistClient.(api.CircuitReporter); ok {
+		response["circuit"] = breaker.Circuit()
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -8,8 +9,30 @@ import (
 	"github.com/google/uuid"
 	"github.com/vrstep/wawatch-backend/config"
 	"github.com/vrstep/wawatch-backend/models"
+	"github.com/vrstep/wawatch-backend/providers"
 )
 
+// providerSync reconciles watch providers with AniList streaming links; nil until InitWatchProviders is called
+var providerSync *providers.Syncer
+
+// InitWatchProviders imports AniList streaming links as watch providers whenever details are fetched,
+// and starts the periodic sync. Must be called after InitDetailsCache.
+func InitWatchProviders() {
+	importer := providers.NewImporter(config.DB)
+	detailsCache.OnStore(importer.RecordDetails)
+	providerSync = providers.NewSyncer(importer, detailsCache)
+	providerSync.Start(context.Background())
+}
+
+// GetProviderSyncStatus reports what the last watch provider sync did
+func GetProviderSyncStatus(c *gin.Context) {
+	if providerSync == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Watch provider sync is not running"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": providerSync.Status()})
+}
+
 // UpdateWatchProvider updates an existing watch provider entry
 // TODO: Add admin authorization check if needed
 func UpdateWatchProvider(c *gin.Context) {
@@ -66,7 +89,9 @@ func UpdateWatchProvider(c *gin.Context) {
 	}
 
 	if updated {
-		provider.LastUpdated = time.Now() // Update the timestamp
+		now := time.Now()
+		provider.LastUpdated = now // Update the timestamp
+		provider.EditedAt = &now   // Keeps the AniList import from overwriting the edit
 		if err := config.DB.Save(&provider).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update provider"})
 			return
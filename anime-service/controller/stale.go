@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/cache"
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+// staleWarning is the Warning header of responses built from local data instead of AniList
+const staleWarning = `110 anime-service "Response is Stale"`
+
+// animeStore keeps list results in anime_caches to serve lists while AniList is down; nil until InitDetailsCache is called
+var animeStore *cache.AnimeStore
+
+// listFetcher loads a page of anime
+type listFetcher func(ctx context.Context) ([]models.AnimeCache, int, error)
+
+// upstreamDown reports whether err means AniList couldn't answer (as opposed to answering "no")
+func upstreamDown(err error) bool {
+	return errors.Is(err, api.ErrUpstreamUnavailable) || errors.Is(err, api.ErrRateLimited)
+}
+
+// fetchList loads a page with fetch and keeps its entries in anime_caches.
+// When AniList can't be reached, the page is rebuilt from anime_caches with local instead and stale is true.
+// local may be nil for lists that can't be rebuilt locally.
+func fetchList(c *gin.Context, fetch listFetcher, local listFetcher) (results []models.AnimeCache, total int, stale bool, err error) {
+	ctx := c.Request.Context()
+	results, total, err = fetch(ctx)
+	if err == nil {
+		rememberAnime(results)
+		return results, total, false, nil
+	}
+	// Lists of other sources (?source=mal) are in another ID space than anime_caches
+	if animeStore == nil || local == nil || c.Query("source") != "" || !upstreamDown(err) {
+		return nil, 0, false, err
+	}
+	localResults, localTotal, localErr := local(ctx)
+	if localErr != nil {
+		log.Printf("Warning: Failed to read anime_caches after AniList failed (%v): %v", err, localErr)
+		return nil, 0, false, err
+	}
+	return localResults, localTotal, true, nil
+}
+
+// rememberAnime saves list entries to anime_caches without holding up the response
+func rememberAnime(animes []models.AnimeCache) {
+	if animeStore == nil || len(animes) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cache.BackgroundRefreshTimeout)
+		defer cancel()
+		if err := animeStore.Save(ctx, animes); err != nil {
+			log.Printf("Warning: Failed to save %d anime to anime_caches: %v", len(animes), err)
+		}
+	}()
+}
+
+// markStale flags a response body built from outdated local data, in the body and with a Warning header
+func markStale(c *gin.Context, body gin.H, stale bool) gin.H {
+	if stale {
+		c.Header("Warning", staleWarning)
+		body["stale"] = true
+	}
+	return body
+}
+
+// respondPage writes a page of anime with the usual pagination meta
+func respondPage(c *gin.Context, results []models.AnimeCache, total int, page int, perPage int, stale bool) {
+	c.JSON(http.StatusOK, markStale(c, gin.H{
+		"data": results,
+		"meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total},
+	}, stale))
+}
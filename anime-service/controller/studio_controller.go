@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+)
+
+// GetStudio fetches a studio profile with a page of its anime
+func GetStudio(c *gin.Context) {
+	studioID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid studio ID format"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+	perPage = min(perPage, api.MaxPerPage)
+
+	studio, works, total, err := anilistClient.GetStudio(c.Request.Context(), studioID, page, perPage)
+	if err != nil {
+		respondError(c, err, "Studio not found on AniList", "Failed to fetch studio")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"studio": studio,
+		"data":   works,
+		"meta":   gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total},
+	})
+}
+
+// GetStaff fetches a staff profile with a page of their anime.
+// ?works=staff (default) lists staff credits, ?works=voice lists voice acting roles.
+func GetStaff(c *gin.Context) {
+	staffID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff ID format"})
+		return
+	}
+	works := strings.ToLower(c.DefaultQuery("works", api.StaffWorksProduction))
+	if works != api.StaffWorksProduction && works != api.StaffWorksVoice {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid works. Use staff or voice"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+	if page < 1 || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page and perPage must be positive"})
+		return
+	}
+	perPage = min(perPage, api.MaxPerPage)
+
+	staff, filmography, total, err := anilistClient.GetStaff(c.Request.Context(), staffID, works, page, perPage)
+	if err != nil {
+		respondError(c, err, "Staff not found on AniList", "Failed to fetch staff")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"staff": staff,
+		"data":  filmography,
+		"meta":  gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total},
+	})
+}
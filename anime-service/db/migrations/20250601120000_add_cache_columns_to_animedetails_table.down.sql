@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_anime_details_expires_at;
+
+ALTER TABLE anime_details DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE anime_details DROP COLUMN IF EXISTS fetched_at;
+ALTER TABLE anime_details DROP COLUMN IF EXISTS payload;
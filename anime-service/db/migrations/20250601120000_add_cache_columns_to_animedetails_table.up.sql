@@ -0,0 +1,9 @@
+-- Turn anime_details into a read-through cache for AniList media details.
+-- The full AnimeDetails payload is stored as JSON so new fields don't need a migration each time,
+-- the flat columns from the original migration are kept for querying/debugging.
+ALTER TABLE anime_details ADD COLUMN IF NOT EXISTS payload JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE anime_details ADD COLUMN IF NOT EXISTS fetched_at TIMESTAMPTZ;
+ALTER TABLE anime_details ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+-- Lets the refresher find expired rows quickly
+CREATE INDEX IF NOT EXISTS idx_anime_details_expires_at ON anime_details (expires_at);
@@ -0,0 +1,15 @@
+-- Cross-database ID mapping, keyed by AniList ID (the ID space used everywhere else)
+CREATE TABLE IF NOT EXISTS anime_id_mappings (
+    anilist_id BIGINT PRIMARY KEY,
+    mal_id BIGINT,
+    kitsu_id BIGINT,
+    anidb_id BIGINT,
+    origin VARCHAR(20) NOT NULL DEFAULT 'import', -- 'anilist' (from idMal) or 'import' (offline dataset)
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Foreign IDs are not unique: datasets occasionally map several AniList entries to one MAL/Kitsu entry
+CREATE INDEX IF NOT EXISTS idx_anime_id_mappings_mal_id ON anime_id_mappings (mal_id);
+CREATE INDEX IF NOT EXISTS idx_anime_id_mappings_kitsu_id ON anime_id_mappings (kitsu_id);
+CREATE INDEX IF NOT EXISTS idx_anime_id_mappings_anidb_id ON anime_id_mappings (anidb_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_anime_caches_popularity;
+DROP INDEX IF EXISTS idx_anime_caches_deleted_at;
+DROP INDEX IF EXISTS idx_anime_caches_title;
+
+ALTER TABLE anime_caches DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE anime_caches DROP COLUMN IF EXISTS total_episodes;
+ALTER TABLE anime_caches DROP COLUMN IF EXISTS cover_image;
+ALTER TABLE anime_caches DROP COLUMN IF EXISTS title;
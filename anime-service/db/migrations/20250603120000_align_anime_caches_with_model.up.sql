@@ -0,0 +1,16 @@
+-- anime_caches was created with AniList's field names, but the AnimeCache model reads and writes its own columns.
+-- Add the missing ones so list results can be kept locally and served while AniList is unreachable.
+ALTER TABLE anime_caches ADD COLUMN IF NOT EXISTS title VARCHAR(255);
+ALTER TABLE anime_caches ADD COLUMN IF NOT EXISTS cover_image TEXT;
+ALTER TABLE anime_caches ADD COLUMN IF NOT EXISTS total_episodes INT;
+ALTER TABLE anime_caches ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ; -- gorm.Model soft deletes
+
+-- Carry over rows written with the original columns
+UPDATE anime_caches SET title = COALESCE(NULLIF(title_english, ''), NULLIF(title_romaji, ''), title_native) WHERE title IS NULL;
+UPDATE anime_caches SET cover_image = cover_image_large WHERE cover_image IS NULL;
+UPDATE anime_caches SET total_episodes = episodes WHERE total_episodes IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_anime_caches_title ON anime_caches (title);
+CREATE INDEX IF NOT EXISTS idx_anime_caches_deleted_at ON anime_caches (deleted_at);
+-- Orders the lists served from local data
+CREATE INDEX IF NOT EXISTS idx_anime_caches_popularity ON anime_caches (popularity DESC);
@@ -0,0 +1,10 @@
+-- Discovery lists (popular, trending, upcoming, current season) prefetched by the cache warmer.
+-- Each row holds the first pages of one list in AniList's order, so the endpoints can serve them without AniList.
+CREATE TABLE IF NOT EXISTS discovery_lists (
+    name VARCHAR(50) PRIMARY KEY, -- e.g. 'popular', 'season:2025:SPRING'
+    total INT NOT NULL DEFAULT 0, -- Size of the whole list on AniList
+    entries JSONB NOT NULL DEFAULT '[]'::jsonb, -- Prefetched AnimeCache entries, in order
+    warmed_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
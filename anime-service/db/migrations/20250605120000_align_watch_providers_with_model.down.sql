@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_watch_providers_external_link;
+DROP INDEX IF EXISTS idx_watch_providers_deleted_at;
+
+ALTER TABLE watch_providers DROP COLUMN IF EXISTS edited_at;
+ALTER TABLE watch_providers DROP COLUMN IF EXISTS external_link_id;
+ALTER TABLE watch_providers DROP COLUMN IF EXISTS source;
+ALTER TABLE watch_providers DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE watch_providers DROP COLUMN IF EXISTS last_updated;
+ALTER TABLE watch_providers DROP COLUMN IF EXISTS is_dub;
+ALTER TABLE watch_providers DROP COLUMN IF EXISTS is_sub;
+
+ALTER TABLE watch_providers DROP CONSTRAINT IF EXISTS watch_providers_pkey;
+ALTER TABLE watch_providers ALTER COLUMN id DROP DEFAULT;
+CREATE SEQUENCE IF NOT EXISTS watch_providers_id_seq OWNED BY watch_providers.id;
+ALTER TABLE watch_providers ALTER COLUMN id TYPE INT USING nextval('watch_providers_id_seq');
+ALTER TABLE watch_providers ALTER COLUMN id SET DEFAULT nextval('watch_providers_id_seq');
+ALTER TABLE watch_providers ADD PRIMARY KEY (id);
@@ -0,0 +1,22 @@
+-- watch_providers was created with a SERIAL id and without several columns of the WatchProvider model
+-- (UUID id, sub/dub flags, gorm.Model soft deletes). Nothing ever inserted rows, so ids are simply regenerated.
+ALTER TABLE watch_providers DROP CONSTRAINT IF EXISTS watch_providers_pkey;
+ALTER TABLE watch_providers ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE watch_providers ALTER COLUMN id TYPE UUID USING gen_random_uuid();
+ALTER TABLE watch_providers ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE watch_providers ADD PRIMARY KEY (id);
+DROP SEQUENCE IF EXISTS watch_providers_id_seq;
+
+ALTER TABLE watch_providers ADD COLUMN IF NOT EXISTS is_sub BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE watch_providers ADD COLUMN IF NOT EXISTS is_dub BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE watch_providers ADD COLUMN IF NOT EXISTS last_updated TIMESTAMPTZ;
+ALTER TABLE watch_providers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Rows imported from AniList's streaming links. source stays NULL for rows entered by hand,
+-- edited_at is set by manual edits, which the import then leaves alone.
+ALTER TABLE watch_providers ADD COLUMN IF NOT EXISTS source VARCHAR(20);
+ALTER TABLE watch_providers ADD COLUMN IF NOT EXISTS external_link_id INT;
+ALTER TABLE watch_providers ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_watch_providers_deleted_at ON watch_providers (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_watch_providers_external_link ON watch_providers (anime_id, external_link_id) WHERE external_link_id IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_anime_caches_search_titles_trgm;
+DROP INDEX IF EXISTS idx_anime_caches_search_titles_fts;
+
+ALTER TABLE anime_caches DROP COLUMN IF EXISTS search_titles;
+ALTER TABLE anime_caches DROP COLUMN IF EXISTS synonyms;
+-- pg_trgm is left installed, other objects may depend on it
@@ -0,0 +1,13 @@
+-- Local title search. Every title variant AniList returns is kept (title_romaji/english/native were not written so far)
+-- and search_titles joins them with the synonyms, indexed for full-text and trigram (typo tolerant) matching.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE anime_caches ADD COLUMN IF NOT EXISTS synonyms JSONB;
+ALTER TABLE anime_caches ADD COLUMN IF NOT EXISTS search_titles TEXT;
+
+-- Rows saved before are searchable by the titles they have until they are saved again
+UPDATE anime_caches SET search_titles = concat_ws(E'\n', title, NULLIF(title_romaji, ''), NULLIF(title_english, ''), NULLIF(title_native, ''))
+WHERE search_titles IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_anime_caches_search_titles_fts ON anime_caches USING GIN (to_tsvector('simple', coalesce(search_titles, '')));
+CREATE INDEX IF NOT EXISTS idx_anime_caches_search_titles_trgm ON anime_caches USING GIN (search_titles gin_trgm_ops);
@@ -0,0 +1,3 @@
+UPDATE anime_caches SET is_adult = FALSE WHERE is_adult IS NULL;
+ALTER TABLE anime_caches ALTER COLUMN is_adult SET DEFAULT FALSE;
+-- Backfilled values and cleared discovery lists are not restored
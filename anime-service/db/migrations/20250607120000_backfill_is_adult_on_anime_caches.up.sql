@@ -0,0 +1,9 @@
+-- anime_caches.is_adult was never written so far, stored rows say FALSE without knowing.
+-- Rows take the flag from details fetched with it (since isAdult is part of the payload),
+-- the others become unknown and stay out of lists until a list save or a details fetch fills it in.
+UPDATE anime_caches c SET is_adult = (d.payload->>'isAdult')::boolean FROM anime_details d WHERE d.id = c.id AND d.payload->'isAdult' IS NOT NULL;
+UPDATE anime_caches c SET is_adult = NULL WHERE NOT EXISTS (SELECT 1 FROM anime_details d WHERE d.id = c.id AND d.payload->'isAdult' IS NOT NULL);
+ALTER TABLE anime_caches ALTER COLUMN is_adult DROP DEFAULT;
+
+-- Prefetched lists were stored before adult anime were left out of them, the cache warmer refills them
+DELETE FROM discovery_lists;
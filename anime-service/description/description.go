@@ -0,0 +1,360 @@
+package description
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/vrstep/wawatch-backend/models"
+	xhtml "golang.org/x/net/html"
+)
+
+// Output formats of Render
+const (
+	FormatHTML     = "html"     // Allowlisted tags only, safe to embed in a page
+	FormatMarkdown = "markdown" // CommonMark, spoilers wrapped in AniList's ~!...!~
+	FormatPlain    = "plain"    // No markup, spoilers wrapped in [Spoiler]...[/Spoiler]
+)
+
+// IsFormat reports whether format is one of the supported output formats
+func IsFormat(format string) bool {
+	return format == FormatHTML || format == FormatMarkdown || format == FormatPlain
+}
+
+// allowedTags are the formatting tags kept from the source; everything else is dropped, keeping its text
+var allowedTags = map[string]bool{"b": true, "strong": true, "i": true, "em": true, "a": true, "p": true, "br": true}
+
+// droppedTags have their content dropped along with them
+var droppedTags = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "embed": true, "template": true}
+
+// spoilerClass marks the spoiler spans of AniList's HTML rendering (<span class='markdown_spoiler'>)
+const spoilerClass = "markdown_spoiler"
+
+var (
+	extraNewlines = regexp.MustCompile(`\n{3,}`)
+	extraBreaks   = regexp.MustCompile(`(<br>\n){3,}`)
+	edgeBreaks    = regexp.MustCompile(`^(\s|<br>)+|(\s|<br>)+$`)
+)
+
+// Render sanitizes an anime description (AniList HTML, or the plain text of Jikan and Kitsu) into format.
+// It returns the whole description and the same text split into sections flagged as spoiler or not,
+// so clients can hide spoilers without parsing the markers. Spoilers are read from AniList's
+// ~!...!~ markdown and from its markdown_spoiler spans.
+func Render(raw string, format string) (string, []models.DescriptionSection) {
+	r := &renderer{format: format}
+	r.run(raw)
+	r.endSection()
+
+	var full strings.Builder
+	for _, section := range r.sections {
+		if !section.Spoiler {
+			full.WriteString(section.Text)
+			continue
+		}
+		switch format {
+		case FormatHTML:
+			full.WriteString(`<span class="spoiler">` + section.Text + `</span>`)
+		case FormatMarkdown:
+			full.WriteString("~!" + section.Text + "!~")
+		default:
+			full.WriteString("[Spoiler]" + section.Text + "[/Spoiler]")
+		}
+	}
+	for i := range r.sections {
+		r.sections[i].Text = collapseBreaks(r.sections[i].Text)
+	}
+	return edgeBreaks.ReplaceAllString(collapseBreaks(full.String()), ""), r.sections
+}
+
+// renderer walks the source tokens, writing the current section in the output format
+type renderer struct {
+	format        string
+	sections      []models.DescriptionSection
+	current       strings.Builder
+	spoiler       bool     // The current section is a spoiler
+	open          []string // Allowlisted tags open in the current section, closed when it ends
+	links         []string // Hrefs of the open links, for Markdown
+	afterBreak    bool     // The last output was a line break, so a following newline in the source is redundant
+	markerSpoiler bool     // Inside ~!...!~
+	spoilerSpans  []bool   // For each open span, whether it is a spoiler span
+	skipDepth     int      // Inside a dropped tag
+}
+
+func (r *renderer) run(raw string) {
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(raw))
+	for {
+		switch tokenizer.Next() {
+		case xhtml.ErrorToken:
+			return // io.EOF, or malformed input; either way what was read is kept
+		case xhtml.TextToken:
+			if r.skipDepth == 0 {
+				r.text(string(tokenizer.Text()))
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if droppedTags[token.Data] {
+				if token.Type == xhtml.StartTagToken {
+					r.skipDepth++
+				}
+				continue
+			}
+			if r.skipDepth == 0 {
+				r.startTag(token)
+			}
+		case xhtml.EndTagToken:
+			token := tokenizer.Token()
+			if droppedTags[token.Data] {
+				if r.skipDepth > 0 {
+					r.skipDepth--
+				}
+				continue
+			}
+			if r.skipDepth == 0 {
+				r.endTag(token.Data)
+			}
+		}
+	}
+}
+
+// text writes source text, splitting it on ~! and !~ spoiler markers
+func (r *renderer) text(s string) {
+	for s != "" {
+		marker := "~!"
+		if r.markerSpoiler {
+			marker = "!~"
+		}
+		before, after, found := strings.Cut(s, marker)
+		r.writeText(before)
+		if !found {
+			return
+		}
+		r.markerSpoiler = !r.markerSpoiler
+		r.updateSpoiler()
+		s = after
+	}
+}
+
+func (r *renderer) writeText(s string) {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			// AniList follows every <br> with a newline, plain text sources only have the newline
+			if !r.afterBreak {
+				r.lineBreak()
+			}
+			r.afterBreak = false
+		}
+		if line == "" {
+			continue
+		}
+		r.afterBreak = false
+		switch r.format {
+		case FormatHTML:
+			r.current.WriteString(html.EscapeString(line))
+		case FormatMarkdown:
+			r.current.WriteString(escapeMarkdown(line))
+		default:
+			r.current.WriteString(line)
+		}
+	}
+}
+
+func (r *renderer) lineBreak() {
+	if r.format == FormatHTML {
+		r.current.WriteString("<br>\n")
+	} else {
+		r.current.WriteString("\n")
+	}
+	r.afterBreak = true
+}
+
+func (r *renderer) startTag(token xhtml.Token) {
+	name := token.Data
+	if name == "span" {
+		r.spoilerSpans = append(r.spoilerSpans, hasClass(token, spoilerClass))
+		r.updateSpoiler()
+		return
+	}
+	if !allowedTags[name] {
+		return
+	}
+	switch name {
+	case "br":
+		r.lineBreak()
+		return
+	case "p":
+		if r.current.Len() > 0 {
+			r.lineBreak()
+			r.lineBreak()
+		}
+		return
+	}
+
+	href := ""
+	if name == "a" {
+		// Links to anything but http(s) (javascript:, data:...) keep only their text
+		if href = safeHref(token); href == "" {
+			return
+		}
+		r.links = append(r.links, href)
+	}
+	r.open = append(r.open, name)
+	r.afterBreak = false
+	switch r.format {
+	case FormatHTML:
+		if name == "a" {
+			r.current.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer" target="_blank">`)
+			return
+		}
+		r.current.WriteString("<" + name + ">")
+	case FormatMarkdown:
+		r.current.WriteString(markdownDelimiter(name, true))
+	}
+}
+
+func (r *renderer) endTag(name string) {
+	if name == "span" {
+		if n := len(r.spoilerSpans); n > 0 {
+			r.spoilerSpans = r.spoilerSpans[:n-1]
+			r.updateSpoiler()
+		}
+		return
+	}
+	if name == "p" && r.current.Len() > 0 {
+		r.lineBreak()
+		r.lineBreak()
+		return
+	}
+	// Unbalanced closing tags are ignored, tags left open by a section end are closed with it
+	for i := len(r.open) - 1; i >= 0; i-- {
+		if r.open[i] == name {
+			for len(r.open) > i {
+				r.closeLast()
+			}
+			return
+		}
+	}
+}
+
+func (r *renderer) closeLast() {
+	name := r.open[len(r.open)-1]
+	r.open = r.open[:len(r.open)-1]
+	href := ""
+	if name == "a" {
+		href = r.links[len(r.links)-1]
+		r.links = r.links[:len(r.links)-1]
+	}
+	switch r.format {
+	case FormatHTML:
+		r.current.WriteString("</" + name + ">")
+	case FormatMarkdown:
+		if name == "a" {
+			r.current.WriteString("](" + strings.NewReplacer("(", "%28", ")", "%29").Replace(href) + ")")
+			return
+		}
+		// CommonMark ignores emphasis closed right after a space, so trailing spaces move past the delimiter
+		text := r.current.String()
+		trimmed := strings.TrimRight(text, " ")
+		r.current.Reset()
+		r.current.WriteString(trimmed + markdownDelimiter(name, false) + text[len(trimmed):])
+	case FormatPlain:
+		if name == "a" {
+			r.current.WriteString(" (" + href + ")")
+		}
+	}
+}
+
+// updateSpoiler starts or ends a spoiler section after a ~! marker or a spoiler span opened or closed.
+// Markers and spans may nest, the text is a spoiler while inside any of them.
+func (r *renderer) updateSpoiler() {
+	spoiler := r.markerSpoiler
+	for _, isSpoiler := range r.spoilerSpans {
+		spoiler = spoiler || isSpoiler
+	}
+	r.setSpoiler(spoiler)
+}
+
+// setSpoiler ends the current section and starts a spoiler or a regular one
+func (r *renderer) setSpoiler(spoiler bool) {
+	if r.spoiler == spoiler {
+		return
+	}
+	r.endSection()
+	r.spoiler = spoiler
+}
+
+func (r *renderer) endSection() {
+	for len(r.open) > 0 {
+		r.closeLast()
+	}
+	if r.current.Len() > 0 {
+		r.sections = append(r.sections, models.DescriptionSection{Text: r.current.String(), Spoiler: r.spoiler})
+	}
+	r.current.Reset()
+}
+
+func markdownDelimiter(tag string, opening bool) string {
+	switch tag {
+	case "b", "strong":
+		return "**"
+	case "i", "em":
+		return "*"
+	case "a":
+		if opening {
+			return "["
+		}
+		return "]"
+	}
+	return ""
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// safeHref returns a link's target when it is an absolute http(s) URL, "" otherwise
+func safeHref(token xhtml.Token) string {
+	for _, attr := range token.Attr {
+		if attr.Key != "href" {
+			continue
+		}
+		u, err := url.Parse(strings.TrimSpace(attr.Val))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ""
+		}
+		return u.String()
+	}
+	return ""
+}
+
+func hasClass(token xhtml.Token, class string) bool {
+	for _, attr := range token.Attr {
+		if attr.Key == "class" {
+			for _, c := range strings.Fields(attr.Val) {
+				if c == class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// collapseBreaks limits runs of line breaks to one blank line
+func collapseBreaks(s string) string {
+	return extraBreaks.ReplaceAllString(extraNewlines.ReplaceAllString(s, "\n\n"), "<br>\n<br>\n")
+}
+
+// Apply returns a copy of details with the description rendered in format and split into sections
+func Apply(details *models.AnimeDetails, format string) *models.AnimeDetails {
+	if details == nil {
+		return nil
+	}
+	rendered := *details
+	rendered.Description, rendered.DescriptionSections = Render(details.Description, format)
+	return &rendered
+}
@@ -0,0 +1,226 @@
+package description
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		format string
+		want   string
+	}{
+		{
+			name:   "script and style content dropped",
+			raw:    `Intro<script>alert("x")</script> and<style>b { color: red }</style> outro`,
+			format: FormatHTML,
+			want:   "Intro and outro",
+		},
+		{
+			name:   "unknown tags keep their text",
+			raw:    `<div onclick="x()">Some <u>text</u></div>`,
+			format: FormatHTML,
+			want:   "Some text",
+		},
+		{
+			name:   "javascript href keeps only the link text",
+			raw:    `<a href="javascript:alert(1)">bad</a> link`,
+			format: FormatHTML,
+			want:   "bad link",
+		},
+		{
+			name:   "data href keeps only the link text",
+			raw:    `<a href="data:text/html;base64,PHNjcmlwdD4=">bad</a>`,
+			format: FormatMarkdown,
+			want:   "bad",
+		},
+		{
+			name:   "relative href keeps only the link text",
+			raw:    `<a href="/anime/1">bad</a>`,
+			format: FormatPlain,
+			want:   "bad",
+		},
+		{
+			name:   "html attributes and entities escaped",
+			raw:    `<a href="https://anilist.co/search?a=1&amp;b=&quot;2&quot;">Tom &amp; Jerry &lt;3</a>`,
+			format: FormatHTML,
+			want:   `<a href="https://anilist.co/search?a=1&amp;b=&#34;2&#34;" rel="nofollow noopener noreferrer" target="_blank">Tom &amp; Jerry &lt;3</a>`,
+		},
+		{
+			name:   "html text escaped",
+			raw:    `5 &gt; 3 &amp; "quotes" <b>bold</b>`,
+			format: FormatHTML,
+			want:   `5 &gt; 3 &amp; &#34;quotes&#34; <b>bold</b>`,
+		},
+		{
+			name:   "markdown special characters escaped",
+			raw:    `[Not a link](x) &lt;tag&gt; *stars* _under_ # hash`,
+			format: FormatMarkdown,
+			want:   `\[Not a link\](x) \<tag> \*stars\* \_under\_ \# hash`,
+		},
+		{
+			name:   "markdown href parentheses encoded",
+			raw:    `<a href="https://en.wikipedia.org/wiki/Gintama_(manga)">Gin[tama]</a>`,
+			format: FormatMarkdown,
+			want:   `[Gin\[tama\]](https://en.wikipedia.org/wiki/Gintama_%28manga%29)`,
+		},
+		{
+			name:   "markdown formatting",
+			raw:    `<b>Bold</b> and <i>italic</i><br>`,
+			format: FormatMarkdown,
+			want:   "**Bold** and *italic*",
+		},
+		{
+			name:   "markdown emphasis closed before trailing spaces",
+			raw:    `<b>Bold </b>word`,
+			format: FormatMarkdown,
+			want:   "**Bold** word",
+		},
+		{
+			name:   "plain link with its target",
+			raw:    `See <a href="https://anilist.co">AniList</a>.`,
+			format: FormatPlain,
+			want:   "See AniList (https://anilist.co).",
+		},
+		{
+			name:   "line breaks collapsed",
+			raw:    "First<br>\n<br>\n<br>\n<br>\nSecond<br><br>",
+			format: FormatHTML,
+			want:   "First<br>\n<br>\nSecond",
+		},
+		{
+			name:   "plain text newlines",
+			raw:    "First\n\n\n\nSecond",
+			format: FormatPlain,
+			want:   "First\n\nSecond",
+		},
+		{
+			name:   "markdown spoiler markers",
+			raw:    "He wins. ~!He dies!~ The end.",
+			format: FormatPlain,
+			want:   "He wins. [Spoiler]He dies[/Spoiler] The end.",
+		},
+		{
+			name:   "spoiler span",
+			raw:    `Before <span class='markdown_spoiler'>secret</span> after`,
+			format: FormatHTML,
+			want:   `Before <span class="spoiler">secret</span> after`,
+		},
+		{
+			name:   "spoiler span spanning tags",
+			raw:    `<b>Before <span class="markdown_spoiler">secret</b> still secret</span> after`,
+			format: FormatHTML,
+			want:   `<b>Before </b><span class="spoiler">secret still secret</span> after`,
+		},
+		{
+			name:   "spoiler marker inside a tag",
+			raw:    `<i>Hidden ~!twist</i> revealed!~ done`,
+			format: FormatMarkdown,
+			want:   `*Hidden* ~!twist revealed!~ done`,
+		},
+		{
+			name:   "unbalanced closing tags ignored",
+			raw:    `Text</b></i> more</a>`,
+			format: FormatHTML,
+			want:   "Text more",
+		},
+		{
+			name:   "unclosed tags closed at the end",
+			raw:    `<b>Bold <i>both`,
+			format: FormatHTML,
+			want:   "<b>Bold <i>both</i></b>",
+		},
+		{
+			name:   "misnested tags closed in order",
+			raw:    `<b>one <i>two</b> three</i>`,
+			format: FormatHTML,
+			want:   "<b>one <i>two</i></b> three",
+		},
+		{
+			name:   "unclosed spoiler runs to the end",
+			raw:    `Start ~!never closed`,
+			format: FormatPlain,
+			want:   "Start [Spoiler]never closed[/Spoiler]",
+		},
+		{
+			name:   "unclosed dropped tag drops the rest",
+			raw:    `Visible<script>hidden`,
+			format: FormatPlain,
+			want:   "Visible",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := Render(tt.raw, tt.format)
+			if got != tt.want {
+				t.Errorf("Render(%q, %s) = %q, want %q", tt.raw, tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSections(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		format string
+		want   []models.DescriptionSection
+	}{
+		{
+			name:   "no spoiler",
+			raw:    "Just a story.",
+			format: FormatPlain,
+			want:   []models.DescriptionSection{{Text: "Just a story."}},
+		},
+		{
+			name:   "markdown spoiler",
+			raw:    "He wins. ~!He dies!~ The end.",
+			format: FormatPlain,
+			want: []models.DescriptionSection{
+				{Text: "He wins. "},
+				{Text: "He dies", Spoiler: true},
+				{Text: " The end."},
+			},
+		},
+		{
+			name:   "spoiler span spanning tags closes them per section",
+			raw:    `<b>Before <span class="markdown_spoiler">secret</b> more</span> after`,
+			format: FormatMarkdown,
+			want: []models.DescriptionSection{
+				{Text: "**Before** "},
+				{Text: "secret more", Spoiler: true},
+				{Text: " after"},
+			},
+		},
+		{
+			name:   "nested spans inside a spoiler",
+			raw:    `<span class="markdown_spoiler">a <span>b</span> c</span> d`,
+			format: FormatHTML,
+			want: []models.DescriptionSection{
+				{Text: "a b c", Spoiler: true},
+				{Text: " d"},
+			},
+		},
+		{
+			name:   "spoiler marker inside a spoiler span",
+			raw:    `<span class="markdown_spoiler">~!hidden!~</span> shown`,
+			format: FormatPlain,
+			want: []models.DescriptionSection{
+				{Text: "hidden", Spoiler: true},
+				{Text: " shown"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := Render(tt.raw, tt.format)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Render(%q, %s) sections = %+v, want %+v", tt.raw, tt.format, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package franchise
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+const (
+	// DefaultMaxDepth is how many relation hops are followed when none is requested
+	DefaultMaxDepth = 3
+	// MaxDepthLimit is the largest depth callers may ask for
+	MaxDepthLimit = 6
+	// MaxNodes bounds the number of anime fetched for one franchise, whatever the depth
+	MaxNodes = 40
+)
+
+// followedRelations are the relation types that stay within a franchise's anime.
+// Adaptations, sources and character crossovers are left out on purpose.
+var followedRelations = map[string]bool{
+	"PREQUEL":     true,
+	"SEQUEL":      true,
+	"PARENT":      true,
+	"SIDE_STORY":  true,
+	"SPIN_OFF":    true,
+	"ALTERNATIVE": true,
+	"SUMMARY":     true,
+	"COMPILATION": true,
+	"CONTAINS":    true,
+}
+
+// RelationsSource fetches an anime's summary and direct relations (implemented by api.AniListAPI)
+type RelationsSource interface {
+	GetAnimeRelations(ctx context.Context, id int) (*models.RelatedMedia, []models.RelationEdge, error)
+}
+
+// Build walks the relation graph breadth-first from rootID up to maxDepth hops and returns
+// the franchise with a suggested watch order. Each anime is visited once, so cycles are harmless.
+func Build(ctx context.Context, source RelationsSource, rootID int, maxDepth int) (*models.Franchise, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if maxDepth > MaxDepthLimit {
+		maxDepth = MaxDepthLimit
+	}
+
+	media := make(map[int]models.RelatedMedia)
+	depth := map[int]int{rootID: 0}
+	linkSeen := make(map[[2]int]bool)
+	var links []models.FranchiseLink
+	truncated := false
+
+	queue := []int{rootID}
+	fetched := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if depth[id] >= maxDepth || fetched >= MaxNodes {
+			// Known through a relation but not expanded
+			truncated = true
+			continue
+		}
+
+		self, edges, err := source.GetAnimeRelations(ctx, id)
+		fetched++
+		if err != nil {
+			// No partial franchise once the caller is gone or out of time
+			if id == rootID || ctx.Err() != nil {
+				return nil, err
+			}
+			log.Printf("Warning: Skipping relations of anime ID %d while building franchise of %d: %v", id, rootID, err)
+			continue
+		}
+		media[id] = *self
+
+		for _, e := range edges {
+			if e.Node.Type != "ANIME" || !followedRelations[e.RelationType] {
+				continue
+			}
+			to := e.Node.ID
+			// A pair is usually listed from both sides (SEQUEL one way, PREQUEL the other), keep one
+			if !linkSeen[[2]int{id, to}] && !linkSeen[[2]int{to, id}] {
+				linkSeen[[2]int{id, to}] = true
+				links = append(links, models.FranchiseLink{From: id, To: to, RelationType: e.RelationType})
+			}
+			if _, seen := depth[to]; seen {
+				continue
+			}
+			depth[to] = depth[id] + 1
+			media[to] = e.Node
+			queue = append(queue, to)
+		}
+	}
+
+	return &models.Franchise{
+		RootID:     rootID,
+		WatchOrder: watchOrder(media, depth, links),
+		Links:      links,
+		Truncated:  truncated,
+	}, nil
+}
+
+// dateKey orders media by start date, unknown dates last
+func dateKey(m models.RelatedMedia) int {
+	if m.StartDate.Year == 0 {
+		return 99999999
+	}
+	month, day := m.StartDate.Month, m.StartDate.Day
+	if month == 0 {
+		month = 12
+	}
+	if day == 0 {
+		day = 31
+	}
+	return m.StartDate.Year*10000 + month*100 + day
+}
+
+// watchOrder sorts the franchise so prequels come before sequels and main stories before side stories,
+// using release date to order everything else (Kahn's algorithm, earliest release first)
+func watchOrder(media map[int]models.RelatedMedia, depth map[int]int, links []models.FranchiseLink) []models.FranchiseEntry {
+	after := make(map[int][]int) // id -> ids that must come after it
+	indegree := make(map[int]int, len(media))
+	for id := range media {
+		indegree[id] = 0
+	}
+	for _, l := range links {
+		first, second := l.From, l.To
+		switch l.RelationType {
+		case "SEQUEL", "SIDE_STORY": // To follows From
+		case "PREQUEL", "PARENT": // To comes before From
+			first, second = l.To, l.From
+		default:
+			continue
+		}
+		if _, ok := media[first]; !ok {
+			continue
+		}
+		if _, ok := media[second]; !ok {
+			continue
+		}
+		after[first] = append(after[first], second)
+		indegree[second]++
+	}
+
+	less := func(a, b int) bool {
+		ka, kb := dateKey(media[a]), dateKey(media[b])
+		if ka != kb {
+			return ka < kb
+		}
+		return a < b
+	}
+
+	var ready []int
+	for id, d := range indegree {
+		if d == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]int, 0, len(media))
+	placed := make(map[int]bool, len(media))
+	for len(order) < len(media) {
+		if len(ready) == 0 {
+			// A prequel/sequel cycle in the data: release the earliest remaining entry to break it
+			for id := range media {
+				if !placed[id] && (len(ready) == 0 || less(id, ready[0])) {
+					ready = []int{id}
+				}
+			}
+		}
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		id := ready[0]
+		ready = ready[1:]
+		if placed[id] {
+			continue
+		}
+		placed[id] = true
+		order = append(order, id)
+		for _, next := range after[id] {
+			indegree[next]--
+			if indegree[next] == 0 && !placed[next] {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	entries := make([]models.FranchiseEntry, len(order))
+	for i, id := range order {
+		entries[i] = models.FranchiseEntry{Position: i + 1, Depth: depth[id], Anime: media[id]}
+	}
+	return entries
+}
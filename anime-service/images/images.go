@@ -0,0 +1,310 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/api"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Decoding only, variants are re-encoded as JPEG
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultDir is where images are stored when ANIME_IMAGE_CACHE_DIR is not set
+	DefaultDir = "data/images"
+	// MaxSourceBytes bounds the size of a downloaded source image
+	MaxSourceBytes = 10 << 20
+	// MaxSourcePixels bounds the dimensions of a source image, so decoding can't exhaust memory
+	MaxSourcePixels = 8000 * 8000
+	// jpegQuality is used for every JPEG variant
+	jpegQuality = 85
+	// fetchTimeout bounds the download of a source image. Kept well below the image route's deadline,
+	// so a slow CDN still leaves time to serve the previously downloaded image.
+	fetchTimeout = 10 * time.Second
+)
+
+// Kinds of image served per anime
+const (
+	KindCover  = "cover"
+	KindBanner = "banner"
+)
+
+// Widths are the variant widths produced. Requested widths are rounded up to the next one,
+// so clients asking for arbitrary sizes share a few cached files.
+var Widths = []int{100, 200, 300, 460, 700, 1000, 1900}
+
+// ErrNoImage is returned when AniList has no image of the requested kind for an anime
+var ErrNoImage = fmt.Errorf("no image available: %w", api.ErrNotFound)
+
+// Image is a stored image file, named by its content so it never changes
+type Image struct {
+	Path        string
+	ContentType string
+	ETag        string // Strong, derived from the content hash of the source and the width
+	ModTime     time.Time
+}
+
+// Store downloads source images once and keeps them, and their resized variants, on disk.
+//
+// Layout under the root directory:
+//   - sources/ab/abcdef...: source images, named by the SHA-256 of their content
+//   - variants/ab/abcdef...-w460.jpg: resized variants of a source
+//   - refs/<animeID>-<kind>: the URL a source was last downloaded from and its hash
+//
+// The refs keep images available when AniList rotates URLs or its CDN can't be reached.
+type Store struct {
+	dir        string
+	httpClient *http.Client
+	group      singleflight.Group
+}
+
+// NewStore creates a store in ANIME_IMAGE_CACHE_DIR (DefaultDir when not set)
+func NewStore() *Store {
+	dir := os.Getenv("ANIME_IMAGE_CACHE_DIR")
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Store{
+		dir:        dir,
+		httpClient: &http.Client{Timeout: fetchTimeout},
+	}
+}
+
+// SnapWidth rounds a requested width up to the next variant width. 0 means the source itself.
+func SnapWidth(width int) int {
+	if width <= 0 {
+		return 0
+	}
+	for _, w := range Widths {
+		if width <= w {
+			return w
+		}
+	}
+	return Widths[len(Widths)-1]
+}
+
+// Get returns the image of an anime at the given variant width (0 for the source image).
+// sourceURL is the current AniList URL of the image, or "" when it could not be looked up,
+// in which case the last downloaded image is served.
+func (s *Store) Get(ctx context.Context, animeID int, kind string, sourceURL string, width int) (*Image, error) {
+	hash, err := s.source(ctx, animeID, kind, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	if width == 0 {
+		return s.stat(s.sourcePath(hash), hash, 0)
+	}
+	return s.variant(hash, width)
+}
+
+// source returns the content hash of the anime's source image, downloading it if the URL changed
+func (s *Store) source(ctx context.Context, animeID int, kind string, sourceURL string) (string, error) {
+	refPath := filepath.Join(s.dir, "refs", fmt.Sprintf("%d-%s", animeID, kind))
+	refURL, refHash := readRef(refPath)
+	if refHash != "" && (sourceURL == "" || sourceURL == refURL) {
+		return refHash, nil
+	}
+	if sourceURL == "" {
+		return "", ErrNoImage
+	}
+
+	ch := s.group.DoChan("source:"+sourceURL, func() (interface{}, error) {
+		// Shared by every caller waiting for this URL, so it must not end with the request that started it
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+		data, err := s.download(ctx, sourceURL)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		if err := writeFileAtomic(s.sourcePath(hash), data); err != nil {
+			return "", err
+		}
+		return hash, nil
+	})
+	var hash string
+	var err error
+	select {
+	case res := <-ch:
+		hash, err = res.Val.(string), res.Err
+		if err == nil {
+			// Callers sharing the download can come from different anime, each records its own ref
+			err = writeFileAtomic(refPath, []byte(sourceURL+"\n"+hash))
+		}
+	case <-ctx.Done():
+		// The download goes on and is stored for later requests
+		err = ctx.Err()
+	}
+	if err != nil {
+		// An older copy beats a broken image
+		if refHash != "" {
+			log.Printf("Warning: Failed to download %s for anime ID %d, serving the previous image: %v", kind, animeID, err)
+			return refHash, nil
+		}
+		return "", err
+	}
+	return hash, nil
+}
+
+// download fetches a source image, refusing anything that isn't an image of reasonable size
+func (s *Store) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: image download failed: %w", api.ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, &api.UpstreamError{Source: "image CDN", StatusCode: resp.StatusCode}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSourceBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: image download failed: %w", api.ErrUpstreamUnavailable, err)
+	}
+	if len(data) > MaxSourceBytes {
+		return nil, fmt.Errorf("image at %s is larger than %d bytes", url, MaxSourceBytes)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image at %s: %w", url, err)
+	}
+	if config.Width*config.Height > MaxSourcePixels {
+		return nil, fmt.Errorf("image at %s is too large (%dx%d)", url, config.Width, config.Height)
+	}
+	return data, nil
+}
+
+// variant returns a resized copy of a source image, creating it on first use.
+// PNG sources stay PNG (covers may have transparency), anything else becomes JPEG.
+// Sources narrower than the width are not upscaled.
+func (s *Store) variant(hash string, width int) (*Image, error) {
+	sourcePath := s.sourcePath(hash)
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= width {
+		return s.stat(sourcePath, hash, 0)
+	}
+
+	ext := ".jpg"
+	if format == "png" {
+		ext = ".png"
+	}
+	path := filepath.Join(s.dir, "variants", hash[:2], hash+"-w"+strconv.Itoa(width)+ext)
+	if img, err := s.stat(path, hash, width); err == nil {
+		return img, nil
+	}
+
+	_, err, _ = s.group.Do("variant:"+path, func() (interface{}, error) {
+		src, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		bounds := src.Bounds()
+		height := bounds.Dy() * width / bounds.Dx()
+		if height < 1 {
+			height = 1
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+		var buf bytes.Buffer
+		if ext == ".png" {
+			err = png.Encode(&buf, dst)
+		} else {
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, writeFileAtomic(path, buf.Bytes())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resize image %s to %dpx: %w", hash, width, err)
+	}
+	return s.stat(path, hash, width)
+}
+
+func (s *Store) sourcePath(hash string) string {
+	return filepath.Join(s.dir, "sources", hash[:2], hash)
+}
+
+// stat describes a stored file
+func (s *Store) stat(path string, hash string, width int) (*Image, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	return &Image{
+		Path:        path,
+		ContentType: http.DetectContentType(head[:n]),
+		ETag:        fmt.Sprintf(`"%s-w%d"`, hash[:32], width),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// readRef reads the URL and hash of a ref file, empty when there is none
+func readRef(path string) (url string, hash string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Warning: Failed to read image ref %s: %v", path, err)
+		}
+		return "", ""
+	}
+	url, hash, _ = strings.Cut(string(data), "\n")
+	return url, hash
+}
+
+// writeFileAtomic writes a file through a temporary file, so readers never see a partial one
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
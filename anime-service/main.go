@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	// Adjust these import paths based on your actual module name for anime-service
 	"github.com/vrstep/wawatch-backend/config"     // Anime service's config
+	"github.com/vrstep/wawatch-backend/controller" // Anime service's controllers
 	"github.com/vrstep/wawatch-backend/middleware" // Anime service's middleware
 	"github.com/vrstep/wawatch-backend/routes"     // Anime service's routes
 )
@@ -20,15 +21,23 @@ func main() {
 	// 2. Logging Middleware: Logs request details including RequestID, Time, Duration
 	router.Use(middleware.Logging()) // Use the enhanced logging middleware
 
-	// 3. CORS Middleware: Allow requests from your frontend
+	// 3. Adult Content Middleware: ?includeAdult=true lets adult anime into lists, they are left out otherwise.
+	// Before Deadline, route-level deadlines start over from the context it saw.
+	router.Use(middleware.AdultContent())
+
+	// 4. Deadline Middleware: Stops work (AniList calls included) for timed out or disconnected requests.
+	// Slow routes set their own, longer deadline.
+	router.Use(middleware.Deadline(middleware.DefaultDeadline))
+
+	// 5. CORS Middleware: Allow requests from your frontend
 	router.Use(func(c *gin.Context) {
 		// Replace "*" with your frontend origin in production for security
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		// Ensure X-Request-ID is allowed and exposed
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, If-None-Match")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH") // Added PATCH
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")                          // Expose the Request ID
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Warning, ETag")           // Expose the Request ID, stale data warnings and validators
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -42,11 +51,19 @@ func main() {
 	// Connect to the database specific to the anime service
 	// This ConnectDB should also handle running migrations for anime_caches, watch_providers
 	config.ConnectDB()
+	controller.InitDetailsCache()   // Read-through cache for /anime/:id, needs the DB
+	controller.InitIDMappings()     // AniList <-> MAL/Kitsu/AniDB ID mapping, needs the DB
+	controller.InitWatchProviders() // Imports AniList streaming links as watch providers, before the warmer fetches details
+	controller.InitCacheWarmer()    // Prefetches popular/trending/upcoming/current season, needs the details cache
+	controller.InitImageProxy()     // Cover/banner proxy, resolves image URLs through the details cache
 
 	// --- Route Setup ---
 	// Register routes handled by this service
 	routes.AnimeRoute(router)    // Routes like /anime/search, /anime/:id, /anime/popular etc.
 	routes.ProviderRoute(router) // Routes like /providers/:id (PUT, DELETE)
+	routes.StudioRoute(router)   // Routes like /studios/:id, /staff/:id
+	routes.MetricsRoute(router)  // Routes like /metrics/anilist
+	routes.ImageRoute(router)    // Routes like /images/:animeId/cover?w=300
 
 	// --- Start Server ---
 	// Run on a different port than the main backend service
@@ -0,0 +1,276 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ID spaces the mapping table knows about
+const (
+	SourceAniList = "anilist"
+	SourceMAL     = "mal"
+	SourceKitsu   = "kitsu"
+	SourceAniDB   = "anidb"
+)
+
+const (
+	OriginAniList = "anilist" // Learned from AniList's idMal
+	OriginImport  = "import"  // Loaded from an offline dataset
+)
+
+// importBatchSize bounds the number of rows per INSERT when importing a dataset
+const importBatchSize = 500
+
+// ErrNotMapped is returned when no mapping is known for an ID, it matches api.ErrNotFound
+var ErrNotMapped = fmt.Errorf("no ID mapping found: %w", api.ErrNotFound)
+
+// columns maps each ID space to its column in anime_id_mappings
+var columns = map[string]string{
+	SourceAniList: "anilist_id",
+	SourceMAL:     "mal_id",
+	SourceKitsu:   "kitsu_id",
+	SourceAniDB:   "anidb_id",
+}
+
+// NormalizeSource maps aliases (e.g. "jikan", "myanimelist") to an ID space name
+func NormalizeSource(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case SourceAniList, "al":
+		return SourceAniList, nil
+	case SourceMAL, api.SourceJikan, "myanimelist":
+		return SourceMAL, nil
+	case SourceKitsu:
+		return SourceKitsu, nil
+	case SourceAniDB:
+		return SourceAniDB, nil
+	default:
+		return "", fmt.Errorf("%w: unknown ID source %q (use anilist, mal, kitsu or anidb)", api.ErrInvalidInput, name)
+	}
+}
+
+// Service resolves anime IDs between AniList, MyAnimeList, Kitsu and AniDB
+type Service struct {
+	db      *gorm.DB
+	anilist api.AniListAPI // Used to resolve MAL IDs missing from the table
+}
+
+var _ api.IDResolver = (*Service)(nil)
+
+// NewService creates a mapping service
+func NewService(db *gorm.DB, anilist api.AniListAPI) *Service {
+	return &Service{db: db, anilist: anilist}
+}
+
+// Lookup returns the mapping for an ID in the given ID space
+func (s *Service) Lookup(ctx context.Context, source string, id int) (*models.AnimeIDMapping, error) {
+	found, err := s.LookupMany(ctx, source, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	m, ok := found[id]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s ID %d", ErrNotMapped, source, id)
+	}
+	return m, nil
+}
+
+// LookupMany returns mappings for several IDs in the same ID space, keyed by the requested ID.
+// MAL IDs missing from the table are resolved through AniList and stored.
+func (s *Service) LookupMany(ctx context.Context, source string, ids []int) (map[int]*models.AnimeIDMapping, error) {
+	source, err := NormalizeSource(source)
+	if err != nil {
+		return nil, err
+	}
+	column := columns[source]
+
+	var rows []models.AnimeIDMapping
+	if err := s.db.WithContext(ctx).Where(column+" IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query ID mappings: %w", err)
+	}
+	found := make(map[int]*models.AnimeIDMapping, len(rows))
+	for i := range rows {
+		if key, ok := idIn(&rows[i], source); ok {
+			// Prefer mappings confirmed by AniList over imported ones when a foreign ID maps twice
+			if existing, dup := found[key]; dup && existing.Origin == OriginAniList {
+				continue
+			}
+			found[key] = &rows[i]
+		}
+	}
+
+	if source == SourceMAL && s.anilist != nil {
+		var missing []int
+		for _, id := range ids {
+			if _, ok := found[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			resolved, err := s.anilist.ResolveMalIDs(ctx, missing)
+			if err != nil {
+				// Serve what the table knows, the rest stays unmapped
+				log.Printf("Warning: Failed to resolve MAL IDs through AniList: %v", err)
+			}
+			for malID, anilistID := range resolved {
+				m, err := s.RecordMalID(ctx, anilistID, malID)
+				if err != nil {
+					log.Printf("Warning: Failed to store mapping AniList %d <-> MAL %d: %v", anilistID, malID, err)
+					continue
+				}
+				found[malID] = m
+			}
+		}
+	}
+	return found, nil
+}
+
+// ResolveID implements api.IDResolver for the metadata fallback chain
+func (s *Service) ResolveID(ctx context.Context, fromSource string, id int, toSource string) (int, error) {
+	to, err := NormalizeSource(toSource)
+	if err != nil {
+		return 0, err
+	}
+	m, err := s.Lookup(ctx, fromSource, id)
+	if err != nil {
+		return 0, err
+	}
+	if foreignID, ok := idIn(m, to); ok {
+		return foreignID, nil
+	}
+	return 0, fmt.Errorf("%w from %s ID %d to %s", ErrNotMapped, fromSource, id, to)
+}
+
+// ResolveIDs implements api.IDResolver, translating several IDs with a single lookup.
+// IDs without a mapping to toSource are left out of the result.
+func (s *Service) ResolveIDs(ctx context.Context, fromSource string, ids []int, toSource string) (map[int]int, error) {
+	to, err := NormalizeSource(toSource)
+	if err != nil {
+		return nil, err
+	}
+	found, err := s.LookupMany(ctx, fromSource, ids)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make(map[int]int, len(found))
+	for id, m := range found {
+		if foreignID, ok := idIn(m, to); ok {
+			resolved[id] = foreignID
+		}
+	}
+	return resolved, nil
+}
+
+// RecordMalID stores the MAL ID AniList reports for an anime, keeping other known IDs
+func (s *Service) RecordMalID(ctx context.Context, anilistID int, malID int) (*models.AnimeIDMapping, error) {
+	m := &models.AnimeIDMapping{AniListID: anilistID, MalID: &malID, Origin: OriginAniList}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "anilist_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mal_id", "origin", "updated_at"}),
+	}).Create(m).Error
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RecordDetails stores the mapping carried by AniList details (idMal), if any
+func (s *Service) RecordDetails(ctx context.Context, details *models.AnimeDetails) {
+	if details.IDMal == nil || details.MetadataSource != "" {
+		return
+	}
+	if _, err := s.RecordMalID(ctx, details.ID, *details.IDMal); err != nil {
+		log.Printf("Warning: Failed to store mapping AniList %d <-> MAL %d: %v", details.ID, *details.IDMal, err)
+	}
+}
+
+// importEntry is one entry of an offline mapping dataset, in the format of
+// https://github.com/Fribb/anime-lists (anime-list-full.json)
+type importEntry struct {
+	AniListID *int `json:"anilist_id"`
+	MalID     *int `json:"mal_id"`
+	KitsuID   *int `json:"kitsu_id"`
+	AniDBID   *int `json:"anidb_id"`
+}
+
+// Import loads an offline mapping dataset (a JSON array) and upserts it.
+// Imported IDs never overwrite known IDs with nulls, and MAL IDs learned from AniList win over imported ones.
+// Returns the number of entries stored.
+func (s *Service) Import(ctx context.Context, r io.Reader) (int, error) {
+	var entries []importEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return 0, fmt.Errorf("%w: failed to parse mapping dataset: %v", api.ErrInvalidInput, err)
+	}
+
+	rows := make([]models.AnimeIDMapping, 0, len(entries))
+	seen := make(map[int]bool, len(entries))
+	for _, e := range entries {
+		// Entries without an AniList ID can't be keyed, duplicates would break the batch upsert
+		if e.AniListID == nil || seen[*e.AniListID] {
+			continue
+		}
+		seen[*e.AniListID] = true
+		rows = append(rows, models.AnimeIDMapping{
+			AniListID: *e.AniListID,
+			MalID:     e.MalID,
+			KitsuID:   e.KitsuID,
+			AniDBID:   e.AniDBID,
+			Origin:    OriginImport,
+		})
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "anilist_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"mal_id":     gorm.Expr("CASE WHEN anime_id_mappings.origin = ? THEN anime_id_mappings.mal_id ELSE COALESCE(EXCLUDED.mal_id, anime_id_mappings.mal_id) END", OriginAniList),
+			"kitsu_id":   gorm.Expr("COALESCE(EXCLUDED.kitsu_id, anime_id_mappings.kitsu_id)"),
+			"anidb_id":   gorm.Expr("COALESCE(EXCLUDED.anidb_id, anime_id_mappings.anidb_id)"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).CreateInBatches(rows, importBatchSize).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to store imported mappings: %w", err)
+	}
+	return len(rows), nil
+}
+
+// ImportFile imports a mapping dataset from disk
+func (s *Service) ImportFile(ctx context.Context, path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return s.Import(ctx, f)
+}
+
+// idIn returns the ID of a mapping in the given ID space
+func idIn(m *models.AnimeIDMapping, source string) (int, bool) {
+	var p *int
+	switch source {
+	case SourceAniList:
+		return m.AniListID, true
+	case SourceMAL:
+		p = m.MalID
+	case SourceKitsu:
+		p = m.KitsuID
+	case SourceAniDB:
+		p = m.AniDBID
+	}
+	if p == nil {
+		return 0, false
+	}
+	return *p, true
+}
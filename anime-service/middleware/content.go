@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/api"
+)
+
+// AdultContent reads ?includeAdult= into the request context, where every list query picks it up
+// (see api.WithAdultContent). Adult anime are left out when it is absent. Invalid values are rejected
+// rather than read as false, so a typo doesn't look like a broken filter.
+// Must run before Deadline, which keeps the context it started from for route-level deadlines.
+func AdultContent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Query("includeAdult")
+		if raw == "" {
+			c.Next()
+			return
+		}
+		include, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid includeAdult, use true or false"})
+			return
+		}
+		c.Request = c.Request.WithContext(api.WithAdultContent(c.Request.Context(), include))
+		c.Next()
+	}
+}
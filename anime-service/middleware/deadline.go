@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultDeadline bounds how long a request may keep working, AniList calls included
+const DefaultDeadline = 15 * time.Second
+
+// deadlineBaseKey stores the request context as it was before any Deadline, so a route-level
+// Deadline can replace a group-level one (including with a longer duration) instead of nesting in it
+const deadlineBaseKey = "DeadlineBaseContext"
+
+// Deadline cancels the request context after d. The context is also cancelled when the client disconnects,
+// so handlers passing c.Request.Context() down stop waiting on upstream calls nobody will read.
+func Deadline(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		base := c.Request.Context()
+		if v, ok := c.Get(deadlineBaseKey); ok {
+			base = v.(context.Context)
+		} else {
+			c.Set(deadlineBaseKey, base)
+		}
+
+		ctx, cancel := context.WithTimeout(base, d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
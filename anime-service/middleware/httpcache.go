@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache-Control policies of the read endpoints
+const (
+	// CacheCatalog suits data that changes a few times a year (genre and tag catalogs)
+	CacheCatalog = "public, max-age=86400"
+	// CacheDiscovery suits the discovery lists, which the cache warmer refreshes every half hour
+	CacheDiscovery = "public, max-age=300, stale-while-revalidate=600"
+	// CacheDetails suits per-anime data (details, characters, relations, studios, staff, ID mappings)
+	CacheDetails = "public, max-age=600, stale-while-revalidate=3600"
+	// CacheShort suits searches, filters and the airing schedule, which users expect to be current
+	CacheShort = "public, max-age=60"
+	// CacheImages suits proxied cover and banner images, which change rarely and are revalidated by ETag
+	CacheImages = "public, max-age=604800, stale-while-revalidate=2592000"
+)
+
+// staleCacheControl replaces the route policy on responses flagged stale (Warning header),
+// so clients revalidate them instead of holding on to outdated local data
+const staleCacheControl = "no-cache"
+
+// HTTPCache sets Cache-Control to policy on successful GET responses, with a strong ETag computed from the body.
+// Requests whose If-None-Match matches that ETag get an empty 304 instead.
+// The handler still runs for every request, only the transfer of unchanged bodies is saved.
+func HTTPCache(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		w := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = original
+
+		if w.status != http.StatusOK {
+			original.WriteHeader(w.status)
+			original.Write(w.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(w.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		header := original.Header()
+		header.Set("ETag", etag)
+		if header.Get("Warning") != "" {
+			header.Set("Cache-Control", staleCacheControl)
+		} else {
+			header.Set("Cache-Control", policy)
+		}
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.WriteHeader(http.StatusOK)
+		original.Write(w.body.Bytes())
+	}
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for (RFC 9110 13.1.2)
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds back the status and body written by the handlers until HTTPCache has looked at them
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// NextAiringEpisode is the next episode of a releasing anime to air
+type NextAiringEpisode struct {
+	Episode         int   `json:"episode"`
+	AiringAt        int64 `json:"airingAt"`        // Unix timestamp (seconds)
+	TimeUntilAiring int64 `json:"timeUntilAiring"` // Seconds, relative to when the payload was served
+}
+
+// AiringScheduleEntry is one episode airing in a time window
+type AiringScheduleEntry struct {
+	ID       int        `json:"id"`
+	Episode  int        `json:"episode"`
+	AiringAt int64      `json:"airingAt"` // Unix timestamp (seconds)
+	Anime    AnimeCache `json:"anime"`
+}
+
+// UpdateTimeUntilAiring recomputes the countdown against now, since cached payloads outlive it
+func (n *NextAiringEpisode) UpdateTimeUntilAiring(now time.Time) {
+	n.TimeUntilAiring = n.AiringAt - now.Unix()
+	if n.TimeUntilAiring < 0 {
+		n.TimeUntilAiring = 0
+	}
+}
@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
 
 // Represents a minimal cache or reference to an anime from the external API
 type AnimeCache struct {
@@ -11,6 +15,46 @@ type AnimeCache struct {
 	CoverImage    string `json:"cover_image"`                              // URL to the cover image
 	Format        string `json:"format"`                                   // e.g., TV, MOVIE, OVA
 	TotalEpisodes *int   `json:"total_episodes"`                           // Pointer for nullable/unknown
+	// Used to order and filter lists served from the local table while AniList is unreachable
+	Status       string `json:"status,omitempty"` // FINISHED, RELEASING, NOT_YET_RELEASED...
+	Season       string `json:"season,omitempty"` // WINTER, SPRING, SUMMER or FALL
+	SeasonYear   int    `json:"season_year,omitempty"`
+	AverageScore int    `json:"average_score,omitempty"`
+	Popularity   int    `json:"popularity,omitempty"`
+	IsAdult      bool   `json:"is_adult"` // Hentai, left out of lists unless the request asks for adult content
+	// Every known title, for local search. Not part of list responses.
+	TitleRomaji  string   `json:"-"`
+	TitleEnglish string   `json:"-"`
+	TitleNative  string   `json:"-"`
+	Synonyms     []string `json:"-" gorm:"serializer:json;type:jsonb"`
+	SearchTitles string   `json:"-"` // All titles above, one per line, indexed for full-text and trigram search
+	// Set when the entry came from another source than AniList. ID is still an AniList ID when the source
+	// stood in for AniList, and in the source's own ID space when it was picked with ?source=
+	MetadataSource string `json:"metadata_source,omitempty" gorm:"-"`
 	// Add other frequently accessed, relatively static fields if needed
 	// LastFetched time.Time `json:"-"` // Track when details were last fetched from API (optional)
 }
+
+// SetTitles stores all known titles of an anime. Title is the English one when there is one,
+// then the romaji one, then the native one.
+func (a *AnimeCache) SetTitles(romaji, english, native string, synonyms []string) {
+	a.TitleRomaji, a.TitleEnglish, a.TitleNative, a.Synonyms = romaji, english, native, synonyms
+	a.Title = english
+	if a.Title == "" {
+		a.Title = romaji
+	}
+	if a.Title == "" {
+		a.Title = native
+	}
+
+	seen := make(map[string]bool)
+	var titles []string
+	for _, t := range append([]string{english, romaji, native}, synonyms...) {
+		t = strings.TrimSpace(t)
+		if t != "" && !seen[strings.ToLower(t)] {
+			seen[strings.ToLower(t)] = true
+			titles = append(titles, t)
+		}
+	}
+	a.SearchTitles = strings.Join(titles, "\n")
+}
@@ -2,12 +2,14 @@ package models
 
 // AnimeDetails represents comprehensive information about an anime
 type AnimeDetails struct {
-	ID    int `json:"id"`
+	ID    int  `json:"id"`
+	IDMal *int `json:"idMal"` // MyAnimeList ID, if AniList knows it
 	Title struct {
 		Romaji  string `json:"romaji"`
 		English string `json:"english"`
 		Native  string `json:"native"`
 	} `json:"title"`
+	Synonyms    []string `json:"synonyms"` // Alternative titles in any language
 	Description string   `json:"description"`
 	Format      string   `json:"format"` // TV, MOVIE, OVA, etc.
 	Status      string   `json:"status"` // FINISHED, RELEASING, etc.
@@ -30,23 +32,67 @@ type AnimeDetails struct {
 		Large  string `json:"large"`
 		Medium string `json:"medium"`
 	} `json:"coverImage"`
-	BannerImage  string `json:"bannerImage"`
-	AverageScore int    `json:"averageScore"`
-	Popularity   int    `json:"popularity"`
-	Studios      struct {
-		Nodes []struct {
-			Name string `json:"name"`
-		} `json:"nodes"`
+	BannerImage     string         `json:"bannerImage"`
+	AverageScore    int            `json:"averageScore"` // Weighted, 0-100
+	MeanScore       int            `json:"meanScore"`    // Plain mean of user scores, 0-100
+	Popularity      int            `json:"popularity"`
+	Favourites      int            `json:"favourites"`
+	CountryOfOrigin string         `json:"countryOfOrigin"` // ISO 3166-1 alpha-2, e.g. "JP"
+	Source          string         `json:"source"`          // ORIGINAL, MANGA, LIGHT_NOVEL, etc.
+	IsAdult         bool           `json:"isAdult"`
+	Hashtag         string         `json:"hashtag"` // Official hashtags, space separated (e.g. "#呪術廻戦 #jujutsukaisen")
+	Trailer         *Trailer       `json:"trailer"`
+	Tags            []AnimeTag     `json:"tags"`
+	ExternalLinks   []ExternalLink `json:"externalLinks"`
+	Studios         struct {
+		Nodes []StudioNode `json:"nodes"`
 	} `json:"studios"`
+	// Next episode to air, nil unless the anime is releasing and AniList knows the schedule
+	NextAiringEpisode *NextAiringEpisode `json:"next_airing_episode"`
+	// Which metadata source produced this entry when it did not come from AniList (e.g. "jikan", "kitsu")
+	MetadataSource string `json:"metadataSource,omitempty"`
+	// Description split into spoiler and non-spoiler parts, set when the description is rendered for a response
+	DescriptionSections []DescriptionSection `json:"descriptionSections,omitempty"`
+}
+
+// DescriptionSection is a part of a rendered description, in the same format as the description itself
+type DescriptionSection struct {
+	Text    string `json:"text"`
+	Spoiler bool   `json:"spoiler"`
+}
+
+// Trailer is a promotional video hosted on YouTube or Dailymotion
+type Trailer struct {
+	ID        string `json:"id"`   // Video ID on the site
+	Site      string `json:"site"` // "youtube" or "dailymotion"
+	Thumbnail string `json:"thumbnail"`
+}
+
+// ExternalLink points to the anime on another site: official website, social media or a streaming service
+type ExternalLink struct {
+	ID         int    `json:"id"`
+	URL        string `json:"url"`
+	Site       string `json:"site"`       // e.g. "Twitter", "Crunchyroll"
+	Type       string `json:"type"`       // INFO, STREAMING or SOCIAL
+	Language   string `json:"language"`   // Language of the linked content, empty when not specific
+	Notes      string `json:"notes"`      // e.g. "Dub" on dubbed streams
+	IsDisabled bool   `json:"isDisabled"` // The link no longer works
 }
 
 // ToAnimeCache converts detailed anime info to a cache entry
 func (a *AnimeDetails) ToAnimeCache() AnimeCache {
-	return AnimeCache{
+	cache := AnimeCache{
 		ID:            a.ID,
-		Title:         a.Title.English,
 		CoverImage:    a.CoverImage.Large,
 		Format:        a.Format,
 		TotalEpisodes: &a.Episodes,
+		Status:        a.Status,
+		Season:        a.Season,
+		SeasonYear:    a.SeasonYear,
+		AverageScore:  a.AverageScore,
+		Popularity:    a.Popularity,
+		IsAdult:       a.IsAdult,
 	}
+	cache.SetTitles(a.Title.Romaji, a.Title.English, a.Title.Native, a.Synonyms)
+	return cache
 }
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AnimeDetailsRecord is a row of the anime_details table, used as a read-through cache
+// for the full AnimeDetails payload fetched from AniList.
+type AnimeDetailsRecord struct {
+	ID               int       `gorm:"primaryKey;autoIncrement:false"` // AniList ID
+	TitleRomaji      string    `gorm:"column:title_romaji"`
+	TitleEnglish     string    `gorm:"column:title_english"`
+	TitleNative      string    `gorm:"column:title_native"`
+	Format           string    `gorm:"column:format"`
+	Status           string    `gorm:"column:status"` // Drives the TTL (RELEASING refreshes more often)
+	Season           string    `gorm:"column:season"`
+	SeasonYear       int       `gorm:"column:season_year"`
+	Episodes         int       `gorm:"column:episodes"`
+	Duration         int       `gorm:"column:duration"`
+	CoverImageLarge  string    `gorm:"column:cover_image_large"`
+	CoverImageMedium string    `gorm:"column:cover_image_medium"`
+	BannerImage      string    `gorm:"column:banner_image"`
+	AverageScore     int       `gorm:"column:average_score"`
+	Popularity       int       `gorm:"column:popularity"`
+	CountryOfOrigin  string    `gorm:"column:country_of_origin"`
+	Source           string    `gorm:"column:source"`
+	MeanScore        int       `gorm:"column:mean_score"`
+	Favourites       int       `gorm:"column:favourites"`
+	IsAdult          bool      `gorm:"column:is_adult"`
+	Payload          string    `gorm:"column:payload;type:jsonb"` // Full AnimeDetails as JSON
+	FetchedAt        time.Time `gorm:"column:fetched_at"`
+	ExpiresAt        time.Time `gorm:"column:expires_at"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// TableName maps the record onto the existing anime_details table
+func (AnimeDetailsRecord) TableName() string {
+	return "anime_details"
+}
+
+// NewAnimeDetailsRecord builds a cache row from AnimeDetails, valid until expiresAt
+func NewAnimeDetailsRecord(a *AnimeDetails, fetchedAt, expiresAt time.Time) (*AnimeDetailsRecord, error) {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return &AnimeDetailsRecord{
+		ID:               a.ID,
+		TitleRomaji:      a.Title.Romaji,
+		TitleEnglish:     a.Title.English,
+		TitleNative:      a.Title.Native,
+		Format:           a.Format,
+		Status:           a.Status,
+		Season:           a.Season,
+		SeasonYear:       a.SeasonYear,
+		Episodes:         a.Episodes,
+		Duration:         a.Duration,
+		CoverImageLarge:  a.CoverImage.Large,
+		CoverImageMedium: a.CoverImage.Medium,
+		BannerImage:      a.BannerImage,
+		AverageScore:     a.AverageScore,
+		Popularity:       a.Popularity,
+		CountryOfOrigin:  a.CountryOfOrigin,
+		Source:           a.Source,
+		MeanScore:        a.MeanScore,
+		Favourites:       a.Favourites,
+		IsAdult:          a.IsAdult,
+		Payload:          string(payload),
+		FetchedAt:        fetchedAt,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+// ToAnimeDetails decodes the stored payload back into AnimeDetails
+func (r *AnimeDetailsRecord) ToAnimeDetails() (*AnimeDetails, error) {
+	var details AnimeDetails
+	if err := json.Unmarshal([]byte(r.Payload), &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// IsExpired reports whether the cached payload is past its TTL
+func (r *AnimeDetailsRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// AnimeIDMapping links an AniList ID to the IDs of the same anime on other databases
+type AnimeIDMapping struct {
+	AniListID int       `json:"anilist_id" gorm:"column:anilist_id;primaryKey;autoIncrement:false"`
+	MalID     *int      `json:"mal_id" gorm:"column:mal_id"`     // MyAnimeList
+	KitsuID   *int      `json:"kitsu_id" gorm:"column:kitsu_id"` // Kitsu
+	AniDBID   *int      `json:"anidb_id" gorm:"column:anidb_id"` // AniDB
+	Origin    string    `json:"origin"`                          // "anilist" (from idMal) or "import" (offline dataset)
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DiscoveryList is a row of the discovery_lists table: the first pages of a discovery list
+// (popular, trending...) as prefetched by the cache warmer
+type DiscoveryList struct {
+	Name      string `gorm:"primaryKey"`
+	Total     int    // Size of the whole list on AniList, not only the prefetched part
+	Entries   string `gorm:"type:jsonb"` // []AnimeCache as JSON, in list order
+	WarmedAt  time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewDiscoveryList builds a row from prefetched entries
+func NewDiscoveryList(name string, total int, entries []AnimeCache, warmedAt time.Time) (*DiscoveryList, error) {
+	payload, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	return &DiscoveryList{Name: name, Total: total, Entries: string(payload), WarmedAt: warmedAt}, nil
+}
+
+// ToAnimeCaches decodes the stored entries
+func (l *DiscoveryList) ToAnimeCaches() ([]AnimeCache, error) {
+	var entries []AnimeCache
+	if err := json.Unmarshal([]byte(l.Entries), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
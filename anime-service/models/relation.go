@@ -0,0 +1,52 @@
+package models
+
+// RelatedMedia is the short form of a media entry reached through a relation (may be manga)
+type RelatedMedia struct {
+	ID    int    `json:"id"`
+	Type  string `json:"type"` // ANIME or MANGA
+	Title struct {
+		Romaji  string `json:"romaji"`
+		English string `json:"english"`
+		Native  string `json:"native"`
+	} `json:"title"`
+	Format     string `json:"format"`
+	Status     string `json:"status"`
+	Episodes   *int   `json:"episodes"`
+	CoverImage struct {
+		Large  string `json:"large"`
+		Medium string `json:"medium"`
+	} `json:"coverImage"`
+	StartDate struct {
+		Year  int `json:"year"`
+		Month int `json:"month"`
+		Day   int `json:"day"`
+	} `json:"startDate"`
+}
+
+// RelationEdge is a direct relation from one media to another
+type RelationEdge struct {
+	RelationType string       `json:"relationType"` // SEQUEL, PREQUEL, SIDE_STORY, PARENT, ADAPTATION, ...
+	Node         RelatedMedia `json:"node"`
+}
+
+// FranchiseLink is a relation between two anime of a franchise
+type FranchiseLink struct {
+	From         int    `json:"from"`
+	To           int    `json:"to"`
+	RelationType string `json:"relationType"`
+}
+
+// FranchiseEntry is an anime in a franchise, in suggested watch order
+type FranchiseEntry struct {
+	Position int          `json:"position"` // 1-based place in the watch order
+	Depth    int          `json:"depth"`    // Relation hops from the requested anime
+	Anime    RelatedMedia `json:"anime"`
+}
+
+// Franchise is the relation graph around an anime with a computed watch order
+type Franchise struct {
+	RootID     int              `json:"rootId"`
+	WatchOrder []FranchiseEntry `json:"watchOrder"`
+	Links      []FranchiseLink  `json:"links"`
+	Truncated  bool             `json:"truncated"` // True when the depth or size limit stopped the walk
+}
@@ -0,0 +1,51 @@
+package models
+
+// StudioNode is the short form of a studio embedded in AnimeDetails
+type StudioNode struct {
+	ID                int    `json:"id"` // AniList studio ID, 0 when the data came from another source
+	Name              string `json:"name"`
+	IsAnimationStudio bool   `json:"isAnimationStudio"`
+}
+
+// Studio is an animation studio or producer profile
+type Studio struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	IsAnimationStudio bool   `json:"isAnimationStudio"`
+	SiteURL           string `json:"siteUrl"`
+	Favourites        int    `json:"favourites"`
+}
+
+// Staff is a staff member or voice actor profile
+type Staff struct {
+	ID   int `json:"id"`
+	Name struct {
+		Full   string `json:"full"`
+		Native string `json:"native"`
+	} `json:"name"`
+	Language string `json:"language"`
+	Image    struct {
+		Large  string `json:"large"`
+		Medium string `json:"medium"`
+	} `json:"image"`
+	Description        string   `json:"description"`
+	PrimaryOccupations []string `json:"primaryOccupations"`
+	Gender             string   `json:"gender"`
+	DateOfBirth        struct {
+		Year  int `json:"year"`
+		Month int `json:"month"`
+		Day   int `json:"day"`
+	} `json:"dateOfBirth"`
+	HomeTown    string `json:"homeTown"`
+	YearsActive []int  `json:"yearsActive"`
+	SiteURL     string `json:"siteUrl"`
+	Favourites  int    `json:"favourites"`
+}
+
+// AnimeWork is an anime in a studio's or staff member's filmography
+type AnimeWork struct {
+	Anime      AnimeCache  `json:"anime"`
+	Roles      []string    `json:"roles,omitempty"`      // Staff roles (e.g. "Director") or character roles (MAIN, SUPPORTING)
+	Characters []Character `json:"characters,omitempty"` // Characters voiced, for voice actor filmographies
+	IsMain     bool        `json:"isMain,omitempty"`     // For studios: main studio rather than producer
+}
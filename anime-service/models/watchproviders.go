@@ -18,6 +18,10 @@ type WatchProvider struct {
 	IsSub        bool      `gorm:"default:false" json:"is_sub"`
 	IsDub        bool      `gorm:"default:false" json:"is_dub"`
 	LastUpdated  time.Time `json:"last_updated"`
+	// Set on rows imported from AniList streaming links, empty on rows entered by hand
+	Source         string     `json:"source,omitempty"`
+	ExternalLinkID *int       `json:"external_link_id,omitempty"` // AniList external link the row was imported from
+	EditedAt       *time.Time `json:"edited_at,omitempty"`        // Last manual edit, imports leave edited rows alone
 
 	// Relationships
 	AnimeCache AnimeCache `gorm:"foreignKey:AnimeID" json:"-"`
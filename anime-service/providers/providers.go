@@ -0,0 +1,173 @@
+package providers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SourceAniList marks watch providers imported from AniList external links
+const SourceAniList = "anilist"
+
+// languageRegions maps the language of a streaming link to the country it targets.
+// Languages spoken in many countries (English, Spanish, Portuguese...) map to no region.
+var languageRegions = map[string]string{
+	"Japanese":   "JP",
+	"Korean":     "KR",
+	"Chinese":    "CN",
+	"Taiwanese":  "TW",
+	"Thai":       "TH",
+	"Vietnamese": "VN",
+	"Indonesian": "ID",
+	"Russian":    "RU",
+	"German":     "DE",
+	"French":     "FR",
+	"Italian":    "IT",
+	"Polish":     "PL",
+	"Turkish":    "TR",
+}
+
+// Result counts what an import changed
+type Result struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
+
+// Add sums up results, e.g. over a sync run
+func (r *Result) Add(other Result) {
+	r.Added += other.Added
+	r.Updated += other.Updated
+	r.Removed += other.Removed
+}
+
+// FromLinks builds watch providers from an anime's working STREAMING links
+func FromLinks(animeID int, links []models.ExternalLink, now time.Time) []models.WatchProvider {
+	var result []models.WatchProvider
+	for _, link := range links {
+		if link.Type != "STREAMING" || link.IsDisabled || link.URL == "" {
+			continue
+		}
+		linkID := link.ID
+		isSub, isDub := audioOf(link)
+		result = append(result, models.WatchProvider{
+			AnimeID:        animeID,
+			ProviderName:   link.Site,
+			ProviderURL:    link.URL,
+			Region:         languageRegions[link.Language],
+			IsSub:          isSub,
+			IsDub:          isDub,
+			LastUpdated:    now,
+			Source:         SourceAniList,
+			ExternalLinkID: &linkID,
+		})
+	}
+	return result
+}
+
+// audioOf guesses what a streaming link plays. AniList marks dubbed streams in their notes ("Dub", "English Dub"),
+// other links stream the original audio, subtitled unless the service itself is Japanese.
+func audioOf(link models.ExternalLink) (isSub bool, isDub bool) {
+	if strings.Contains(strings.ToLower(link.Notes), "dub") {
+		return false, true
+	}
+	return link.Language != "Japanese", false
+}
+
+// Importer keeps the watch providers of an anime in line with its AniList streaming links
+type Importer struct {
+	db *gorm.DB
+}
+
+// NewImporter creates an importer on the watch_providers table
+func NewImporter(db *gorm.DB) *Importer {
+	return &Importer{db: db}
+}
+
+// Import adds, updates and removes the imported providers of an anime to match its streaming links.
+// Rows entered by hand are never touched, nor are imported rows that were edited or deleted by hand since.
+// Details without external links (fallback sources, payloads cached before links were fetched) are skipped.
+func (i *Importer) Import(ctx context.Context, details *models.AnimeDetails) (Result, error) {
+	var result Result
+	if details.MetadataSource != "" || details.ExternalLinks == nil {
+		return result, nil
+	}
+	wanted := FromLinks(details.ID, details.ExternalLinks, time.Now())
+
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []models.WatchProvider
+		if err := tx.Unscoped().Where("anime_id = ? AND source = ?", details.ID, SourceAniList).Find(&existing).Error; err != nil {
+			return err
+		}
+		byLink := make(map[int]*models.WatchProvider, len(existing))
+		for j := range existing {
+			if existing[j].ExternalLinkID != nil {
+				byLink[*existing[j].ExternalLinkID] = &existing[j]
+			}
+		}
+
+		if len(wanted) > 0 {
+			// watch_providers references anime_caches
+			cacheEntry := details.ToAnimeCache()
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&cacheEntry).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, provider := range wanted {
+			row, ok := byLink[*provider.ExternalLinkID]
+			delete(byLink, *provider.ExternalLinkID)
+			switch {
+			case !ok:
+				if err := tx.Omit(clause.Associations).Create(&provider).Error; err != nil {
+					return err
+				}
+				result.Added++
+			case row.DeletedAt.Valid || row.EditedAt != nil:
+				// Changed by hand, the manual version wins
+			case row.ProviderName != provider.ProviderName || row.ProviderURL != provider.ProviderURL ||
+				row.Region != provider.Region || row.IsSub != provider.IsSub || row.IsDub != provider.IsDub:
+				err := tx.Model(row).Updates(map[string]interface{}{
+					"provider_name": provider.ProviderName,
+					"provider_url":  provider.ProviderURL,
+					"region":        provider.Region,
+					"is_sub":        provider.IsSub,
+					"is_dub":        provider.IsDub,
+					"last_updated":  provider.LastUpdated,
+				}).Error
+				if err != nil {
+					return err
+				}
+				result.Updated++
+			}
+		}
+
+		// Links gone from AniList, unless the row was edited by hand
+		for _, row := range byLink {
+			if row.DeletedAt.Valid || row.EditedAt != nil {
+				continue
+			}
+			if err := tx.Unscoped().Delete(row).Error; err != nil {
+				return err
+			}
+			result.Removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// RecordDetails imports the streaming links of freshly fetched details, as a details cache hook
+func (i *Importer) RecordDetails(ctx context.Context, details *models.AnimeDetails) {
+	if _, err := i.Import(ctx, details); err != nil {
+		log.Printf("Warning: Failed to import watch providers of anime ID %d: %v", details.ID, err)
+	}
+}
@@ -0,0 +1,181 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/cache"
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+const (
+	// DefaultSyncInterval is how often the watch providers of every cached anime are reconciled
+	DefaultSyncInterval = 24 * time.Hour
+	// DefaultSyncDelay spaces out details refreshes, leaving most of the AniList budget to user requests
+	DefaultSyncDelay = 2 * time.Second
+)
+
+// SyncStatus reports what the last sync run did
+type SyncStatus struct {
+	Enabled          bool       `json:"enabled"`
+	Interval         string     `json:"interval"`
+	Running          bool       `json:"running"`
+	LastStartedAt    *time.Time `json:"lastStartedAt,omitempty"`
+	LastDuration     string     `json:"lastDuration,omitempty"`
+	Anime            int        `json:"anime"`
+	DetailsRefreshed int        `json:"detailsRefreshed"` // Their links are imported by the details cache hook, not counted in Result
+	Result
+	Errors int `json:"errors"`
+}
+
+// Syncer periodically imports the streaming links of every anime in the details cache.
+// Fetches already import links as they happen. The sync imports the links of current cached details,
+// and refreshes from AniList the details that expired or were cached before links were fetched,
+// so their links are imported with the fresh details.
+type Syncer struct {
+	importer  *Importer
+	details   *cache.AnimeDetailsCache
+	interval  time.Duration
+	delay     time.Duration
+	mu        sync.Mutex
+	status    SyncStatus
+	isRunning bool
+}
+
+// NewSyncer creates a syncer. Settings come from the environment:
+//   - ANIME_PROVIDER_SYNC_INTERVAL: time between runs, e.g. "12h" (defaults to DefaultSyncInterval, "0" disables the sync)
+//   - ANIME_PROVIDER_SYNC_DELAY: pause between details refreshes (defaults to DefaultSyncDelay)
+func NewSyncer(importer *Importer, details *cache.AnimeDetailsCache) *Syncer {
+	s := &Syncer{
+		importer: importer,
+		details:  details,
+		interval: DefaultSyncInterval,
+		delay:    DefaultSyncDelay,
+	}
+	if v := os.Getenv("ANIME_PROVIDER_SYNC_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			s.interval = d
+		} else {
+			log.Printf("Warning: Invalid ANIME_PROVIDER_SYNC_INTERVAL %q, using %s", v, s.interval)
+		}
+	}
+	if v := os.Getenv("ANIME_PROVIDER_SYNC_DELAY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			s.delay = d
+		} else {
+			log.Printf("Warning: Invalid ANIME_PROVIDER_SYNC_DELAY %q, using %s", v, s.delay)
+		}
+	}
+	return s
+}
+
+// Start runs the sync right away and then on every interval until ctx is done
+func (s *Syncer) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		log.Printf("Watch provider sync disabled (ANIME_PROVIDER_SYNC_INTERVAL=0)")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.Run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run imports the streaming links of every cached anime once, refreshing outdated details first.
+// A failing anime is logged and skipped. Refreshes stop for the run when AniList can't be reached.
+func (s *Syncer) Run(ctx context.Context) {
+	s.mu.Lock()
+	if s.isRunning {
+		s.mu.Unlock()
+		return
+	}
+	s.isRunning = true
+	s.mu.Unlock()
+
+	started := time.Now()
+	status := SyncStatus{}
+	var outdated []int
+	err := s.details.Each(ctx, func(details *models.AnimeDetails, expired bool) error {
+		status.Anime++
+		// Links of expired details were imported when they were fetched, the refresh below brings them up to date
+		if expired || details.ExternalLinks == nil {
+			outdated = append(outdated, details.ID)
+			return nil
+		}
+		result, err := s.importer.Import(ctx, details)
+		if err != nil {
+			log.Printf("Warning: Watch provider sync failed for anime ID %d: %v", details.ID, err)
+			status.Errors++
+			return nil
+		}
+		status.Result.Add(result)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Warning: Watch provider sync stopped early: %v", err)
+		status.Errors++
+	}
+
+	for i, id := range outdated {
+		if ctx.Err() != nil {
+			break
+		}
+		refreshCtx, cancel := context.WithTimeout(ctx, cache.BackgroundRefreshTimeout)
+		details, err := s.details.Refresh(refreshCtx, id)
+		cancel()
+		// Details from a fallback source mean AniList is down, they are neither cached nor imported
+		if err == nil && details.MetadataSource != "" {
+			err = fmt.Errorf("%w: details served by %s", api.ErrUpstreamUnavailable, details.MetadataSource)
+		}
+		if err != nil {
+			log.Printf("Warning: Watch provider sync failed to refresh details of anime ID %d: %v", id, err)
+			status.Errors++
+			if errors.Is(err, api.ErrUpstreamUnavailable) || errors.Is(err, api.ErrRateLimited) {
+				log.Printf("Warning: AniList unavailable, watch provider sync skips %d details refreshes until the next run", len(outdated)-i-1)
+				break
+			}
+		} else {
+			status.DetailsRefreshed++
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.delay):
+		}
+	}
+
+	duration := time.Since(started)
+	log.Printf("Watch provider sync checked %d anime in %s: %d details refreshed, %d added, %d updated, %d removed (%d errors)",
+		status.Anime, duration.Round(time.Second), status.DetailsRefreshed, status.Added, status.Updated, status.Removed, status.Errors)
+
+	s.mu.Lock()
+	status.LastStartedAt = &started
+	status.LastDuration = duration.Round(time.Millisecond).String()
+	s.status = status
+	s.isRunning = false
+	s.mu.Unlock()
+}
+
+// Status returns the outcome of the last run
+func (s *Syncer) Status() SyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.Enabled = s.interval > 0
+	status.Interval = s.interval.String()
+	status.Running = s.isRunning
+	return status
+}
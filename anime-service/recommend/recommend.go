@@ -0,0 +1,116 @@
+package recommend
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+const (
+	// MaxSeeds bounds the number of seed anime of one aggregation, each costs an AniList call
+	MaxSeeds = 20
+	// PerSeed is how many of each seed's best rated recommendations are aggregated
+	PerSeed = 25
+	// parallelSeeds bounds the seeds fetched at once, the AniList client still applies its rate limit
+	parallelSeeds = 4
+)
+
+// RecommendationsSource fetches the recommendations of one anime (implemented by api.AniListAPI)
+type RecommendationsSource interface {
+	GetAnimeRecommendations(ctx context.Context, id int, page int, perPage int) ([]models.Recommendation, int, error)
+}
+
+// ForSeeds aggregates the recommendations of several seed anime into one ranked list.
+// Anime recommended by more seeds rank first, then by their rating summed over those seeds.
+// The seeds themselves are left out. Seeds AniList doesn't know are skipped, other failures
+// only fail the whole aggregation when no seed could be read.
+func ForSeeds(ctx context.Context, source RecommendationsSource, seeds []int) ([]models.Recommendation, error) {
+	isSeed := make(map[int]bool, len(seeds))
+	var unique []int
+	for _, id := range seeds {
+		if !isSeed[id] {
+			isSeed[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > MaxSeeds {
+		unique = unique[:MaxSeeds]
+	}
+
+	type seedResult struct {
+		recommendations []models.Recommendation
+		err             error
+	}
+	fetched := make([]seedResult, len(unique))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallelSeeds)
+	for i, id := range unique {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			fetched[i].recommendations, _, fetched[i].err = source.GetAnimeRecommendations(ctx, id, 1, PerSeed)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Recommendation)
+	var firstErr error
+	read := 0
+	for i, id := range unique {
+		if err := fetched[i].err; err != nil {
+			if !errors.Is(err, api.ErrNotFound) {
+				log.Printf("Warning: Failed to fetch recommendations of seed anime ID %d: %v", id, err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+			continue
+		}
+		read++
+		for _, r := range fetched[i].recommendations {
+			if isSeed[r.Anime.ID] {
+				continue
+			}
+			entry, ok := byID[r.Anime.ID]
+			if !ok {
+				entry = &models.Recommendation{Anime: r.Anime}
+				byID[r.Anime.ID] = entry
+			}
+			entry.Rating += r.Rating
+			entry.RecommendedBy = append(entry.RecommendedBy, id)
+		}
+	}
+	if read == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	result := make([]models.Recommendation, 0, len(byID))
+	for _, entry := range byID {
+		sort.Ints(entry.RecommendedBy)
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if len(a.RecommendedBy) != len(b.RecommendedBy) {
+			return len(a.RecommendedBy) > len(b.RecommendedBy)
+		}
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		}
+		if a.Anime.Popularity != b.Anime.Popularity {
+			return a.Anime.Popularity > b.Anime.Popularity
+		}
+		return a.Anime.ID < b.Anime.ID
+	})
+	return result, nil
+}
@@ -1,11 +1,12 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	// Adjust import path to your anime-service module name
 	"github.com/vrstep/wawatch-backend/controller"
-	// Middleware specific to anime-service if needed (e.g., specific logging, rate limiting)
-	// "github.com/vrstep/wawatch-anime-service/middleware"
+	"github.com/vrstep/wawatch-backend/middleware"
 )
 
 // AnimeRoute defines routes related to fetching anime data
@@ -13,20 +14,33 @@ func AnimeRoute(router *gin.Engine) {
 	// Note: No RequireAuth middleware here, as this service trusts the calling service (backend)
 	anime := router.Group("/anime")
 	{
-		anime.GET("/search", controller.SearchAnime)  // Controller needs to be created/moved here
-		anime.GET("/:id", controller.GetAnimeDetails) // Controller needs to be created/moved here
+		anime.GET("/search", middleware.HTTPCache(middleware.CacheShort), controller.SearchAnime)    // Controller needs to be created/moved here
+		anime.GET("/:id", middleware.HTTPCache(middleware.CacheDetails), controller.GetAnimeDetails) // Controller needs to be created/moved here
+		anime.GET("/:id/characters", middleware.HTTPCache(middleware.CacheDetails), controller.GetAnimeCharacters)
+		anime.GET("/:id/recommendations", middleware.HTTPCache(middleware.CacheDetails), controller.GetRecommendationsForAnime) // "If you liked this" row, best rated first
+		anime.GET("/:id/relations", middleware.HTTPCache(middleware.CacheDetails), controller.GetAnimeRelations)
+		anime.GET("/:id/franchise", middleware.HTTPCache(middleware.CacheDetails), middleware.Deadline(time.Minute), controller.GetAnimeFranchise) // Relation graph + suggested watch order, up to MaxNodes AniList calls
 
 		// Public discovery endpoints
-		anime.GET("/popular", controller.GetPopularAnime)               // Controller needs to be created/moved here
-		anime.GET("/trending", controller.GetTrendingAnime)             // Controller needs to be created/moved here
-		anime.GET("/season/:year/:season", controller.GetAnimeBySeason) // Controller needs to be created/moved here
+		anime.GET("/popular", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetPopularAnime)               // Controller needs to be created/moved here
+		anime.GET("/trending", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetTrendingAnime)             // Controller needs to be created/moved here
+		anime.GET("/season/:year/:season", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetAnimeBySeason) // Controller needs to be created/moved here
+
+		anime.GET("/upcoming", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetUpcomingAnime)                           // Controller needs to be created/moved here
+		anime.GET("/recently-released", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetRecentlyReleasedAnime)          // Controller needs to be created/moved here
+		anime.GET("/explore", middleware.HTTPCache(middleware.CacheShort), controller.ExploreAnime)                                    // New explore endpoint
+		anime.GET("/browse", middleware.HTTPCache(middleware.CacheShort), controller.BrowseAnime)                                      // Combined filters, the lists above are presets of it
+		anime.GET("/genres", middleware.HTTPCache(middleware.CacheCatalog), controller.GetGenres)                                      // Genre catalog
+		anime.GET("/tags", middleware.HTTPCache(middleware.CacheCatalog), controller.GetTags)                                          // Tag catalog with categories and spoiler/adult flags
+		anime.GET("/airing", middleware.HTTPCache(middleware.CacheShort), controller.GetAiringSchedule)                                // Episodes airing in a time window (?from=&to=)
+		anime.GET("/batch", middleware.HTTPCache(middleware.CacheDetails), middleware.Deadline(time.Minute), controller.GetAnimeBatch) // Many anime at once (?ids=1,2,3), one AniList call per 50
 
-		// Recommendations endpoint (implementation might differ from user service)
-		anime.GET("/recommendations", controller.GetAnimeRecommendations)    // Controller needs to be created/moved here
-		anime.GET("/upcoming", controller.GetUpcomingAnime)                  // Controller needs to be created/moved here
-		anime.GET("/recently-released", controller.GetRecentlyReleasedAnime) // Controller needs to be created/moved here
-		anime.GET("/explore", controller.ExploreAnime)                       // New explore endpoint
+		// Recommendations for fans of several anime at once
+		anime.GET("/recommendations", middleware.HTTPCache(middleware.CacheDiscovery), middleware.Deadline(time.Minute), controller.GetAnimeRecommendations) // Aggregated over seeds (?ids=1,2,3), one AniList call per seed
 
-		anime.GET("/")
+		// Cross-database ID mapping (AniList <-> MAL <-> Kitsu <-> AniDB)
+		anime.GET("/map", middleware.HTTPCache(middleware.CacheDetails), controller.GetIDMapping)
+		anime.GET("/map/batch", middleware.HTTPCache(middleware.CacheDetails), middleware.Deadline(time.Minute), controller.GetIDMappingsBatch)
+		anime.POST("/map/import", middleware.Deadline(5*time.Minute), controller.ImportIDMappings) // Full datasets hold ~30k entries
 	}
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/controller"
+	"github.com/vrstep/wawatch-backend/middleware"
+)
+
+// ImageRoute defines the cover and banner image proxy
+func ImageRoute(router *gin.Engine) {
+	// Note: No RequireAuth middleware here, images are embedded directly by clients
+	router.GET("/images/:animeId/:kind", middleware.Deadline(30*time.Second), controller.GetAnimeImage) // Details lookup, then a CDN download of up to 10s
+}
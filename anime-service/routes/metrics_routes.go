@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/controller"
+)
+
+// MetricsRoute defines routes exposing operational counters
+func MetricsRoute(router *gin.Engine) {
+	router.GET("/metrics/anilist", controller.GetAniListStats)
+	router.GET("/metrics/warmer", controller.GetWarmerStatus)
+	router.GET("/metrics/providers", controller.GetProviderSyncStatus)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/controller"
+	"github.com/vrstep/wawatch-backend/middleware"
+)
+
+// StudioRoute defines routes for studio and staff profiles with their filmographies
+func StudioRoute(router *gin.Engine) {
+	// Note: No RequireAuth middleware here, as this service trusts the calling service (backend)
+	router.GET("/studios/:id", middleware.HTTPCache(middleware.CacheDetails), controller.GetStudio)
+	router.GET("/staff/:id", middleware.HTTPCache(middleware.CacheDetails), controller.GetStaff)
+}
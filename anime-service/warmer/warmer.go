@@ -0,0 +1,265 @@
+package warmer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vrstep/wawatch-backend/api"
+	"github.com/vrstep/wawatch-backend/cache"
+	"github.com/vrstep/wawatch-backend/models"
+)
+
+const (
+	// DefaultInterval is how often the discovery lists are prefetched
+	DefaultInterval = 30 * time.Minute
+	// DefaultPages is how many pages of each list are prefetched
+	DefaultPages = 3
+	// PerPage is the page size used to prefetch, AniList's maximum
+	PerPage = 50
+	// DefaultDetailsDelay spaces out details fetches, leaving most of the AniList budget to user requests
+	DefaultDetailsDelay = 2 * time.Second
+	// listTimeout bounds the fetch of one list page
+	listTimeout = 30 * time.Second
+)
+
+// Names of the prefetched lists in discovery_lists
+const (
+	ListPopular  = "popular"
+	ListTrending = "trending"
+	ListUpcoming = "upcoming"
+)
+
+// SeasonList names the prefetched list of a season
+func SeasonList(year int, season string) string {
+	return fmt.Sprintf("season:%d:%s", year, season)
+}
+
+// CurrentSeason returns the anime season (by calendar quarter) and year of t
+func CurrentSeason(t time.Time) (int, string) {
+	seasons := [...]string{"WINTER", "SPRING", "SUMMER", "FALL"}
+	return t.Year(), seasons[(int(t.Month())-1)/3]
+}
+
+// Status reports what the last warming run did
+type Status struct {
+	Enabled          bool       `json:"enabled"`
+	Interval         string     `json:"interval"`
+	Running          bool       `json:"running"`
+	LastStartedAt    *time.Time `json:"lastStartedAt,omitempty"`
+	LastDuration     string     `json:"lastDuration,omitempty"`
+	ListsWarmed      int        `json:"listsWarmed"`
+	DetailsRefreshed int        `json:"detailsRefreshed"`
+	Errors           int        `json:"errors"`
+}
+
+// Warmer periodically prefetches the discovery lists (popular, trending, upcoming and the current season)
+// and the details of every anime on them, so the home page is served from the database.
+type Warmer struct {
+	client    api.AniListAPI
+	lists     *cache.DiscoveryStore
+	animes    *cache.AnimeStore
+	details   *cache.AnimeDetailsCache // Optional, details are not prefetched when nil
+	interval  time.Duration
+	pages     int
+	delay     time.Duration
+	mu        sync.Mutex
+	status    Status
+	isRunning bool
+}
+
+// New creates a warmer. Settings come from the environment:
+//   - ANIME_WARMER_INTERVAL: time between runs, e.g. "15m" (defaults to DefaultInterval, "0" disables the warmer)
+//   - ANIME_WARMER_PAGES: pages of PerPage entries prefetched per list (defaults to DefaultPages)
+//   - ANIME_WARMER_DETAILS_DELAY: pause between details fetches (defaults to DefaultDetailsDelay)
+func New(client api.AniListAPI, lists *cache.DiscoveryStore, animes *cache.AnimeStore, details *cache.AnimeDetailsCache) *Warmer {
+	w := &Warmer{
+		client:   client,
+		lists:    lists,
+		animes:   animes,
+		details:  details,
+		interval: DefaultInterval,
+		pages:    DefaultPages,
+		delay:    DefaultDetailsDelay,
+	}
+	if v := os.Getenv("ANIME_WARMER_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			w.interval = d
+		} else {
+			log.Printf("Warning: Invalid ANIME_WARMER_INTERVAL %q, using %s", v, w.interval)
+		}
+	}
+	if v := os.Getenv("ANIME_WARMER_PAGES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			w.pages = n
+		} else {
+			log.Printf("Warning: Invalid ANIME_WARMER_PAGES %q, using %d", v, w.pages)
+		}
+	}
+	if v := os.Getenv("ANIME_WARMER_DETAILS_DELAY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			w.delay = d
+		} else {
+			log.Printf("Warning: Invalid ANIME_WARMER_DETAILS_DELAY %q, using %s", v, w.delay)
+		}
+	}
+	return w
+}
+
+// Enabled reports whether the warmer runs at all
+func (w *Warmer) Enabled() bool {
+	return w.interval > 0
+}
+
+// MaxAge is how old a prefetched list may be and still be served.
+// Past that the warmer is assumed stuck (or AniList down for long) and lists are fetched live.
+func (w *Warmer) MaxAge() time.Duration {
+	return 3 * w.interval
+}
+
+// Start runs the warmer right away and then on every interval until ctx is done
+func (w *Warmer) Start(ctx context.Context) {
+	if !w.Enabled() {
+		log.Printf("Cache warmer disabled (ANIME_WARMER_INTERVAL=0)")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			w.Run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run prefetches every list once, then the details of the anime found on them.
+// A failing list is kept as previously stored, other lists are still warmed.
+func (w *Warmer) Run(ctx context.Context) {
+	w.mu.Lock()
+	if w.isRunning {
+		w.mu.Unlock()
+		return
+	}
+	w.isRunning = true
+	w.mu.Unlock()
+
+	started := time.Now()
+	status := Status{}
+	year, season := CurrentSeason(started)
+	lists := []struct {
+		name  string
+		fetch func(ctx context.Context, page int) ([]models.AnimeCache, int, error)
+	}{
+		{ListPopular, func(ctx context.Context, page int) ([]models.AnimeCache, int, error) {
+			return w.client.GetPopularAnime(ctx, page, PerPage)
+		}},
+		{ListTrending, func(ctx context.Context, page int) ([]models.AnimeCache, int, error) {
+			return w.client.GetTrendingAnime(ctx, page, PerPage)
+		}},
+		{ListUpcoming, func(ctx context.Context, page int) ([]models.AnimeCache, int, error) {
+			return w.client.GetUpcomingAnime(ctx, page, PerPage)
+		}},
+		{SeasonList(year, season), func(ctx context.Context, page int) ([]models.AnimeCache, int, error) {
+			return w.client.GetAnimeBySeason(ctx, year, season, page, PerPage)
+		}},
+	}
+
+	seen := make(map[int]bool)
+	var ids []int
+	for _, l := range lists {
+		entries, err := w.warmList(ctx, l.name, l.fetch)
+		if err != nil {
+			log.Printf("Warning: Cache warmer failed to prefetch %s: %v", l.name, err)
+			status.Errors++
+			continue
+		}
+		status.ListsWarmed++
+		for _, e := range entries {
+			if !seen[e.ID] {
+				seen[e.ID] = true
+				ids = append(ids, e.ID)
+			}
+		}
+	}
+
+	if w.details != nil {
+		for _, id := range ids {
+			if ctx.Err() != nil {
+				break
+			}
+			detailsCtx, cancel := context.WithTimeout(ctx, cache.BackgroundRefreshTimeout)
+			fetched, err := w.details.Warm(detailsCtx, id)
+			cancel()
+			if err != nil {
+				log.Printf("Warning: Cache warmer failed to prefetch details of anime ID %d: %v", id, err)
+				status.Errors++
+			}
+			if fetched {
+				status.DetailsRefreshed++
+				// Only pace actual AniList calls, cached details cost nothing
+				select {
+				case <-ctx.Done():
+				case <-time.After(w.delay):
+				}
+			}
+		}
+	}
+
+	duration := time.Since(started)
+	log.Printf("Cache warmer prefetched %d lists and %d details in %s (%d errors)", status.ListsWarmed, status.DetailsRefreshed, duration.Round(time.Second), status.Errors)
+
+	w.mu.Lock()
+	status.LastStartedAt = &started
+	status.LastDuration = duration.Round(time.Millisecond).String()
+	w.status = status
+	w.isRunning = false
+	w.mu.Unlock()
+}
+
+// warmList fetches the first pages of a list and stores them
+func (w *Warmer) warmList(ctx context.Context, name string, fetch func(ctx context.Context, page int) ([]models.AnimeCache, int, error)) ([]models.AnimeCache, error) {
+	entries := []models.AnimeCache{}
+	total := 0
+	for page := 1; page <= w.pages; page++ {
+		pageCtx, cancel := context.WithTimeout(ctx, listTimeout)
+		results, pageTotal, err := fetch(pageCtx, page)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, results...)
+		total = pageTotal
+		if len(results) < PerPage || page*PerPage >= total {
+			break
+		}
+	}
+	if err := w.lists.Save(ctx, name, total, entries); err != nil {
+		return nil, fmt.Errorf("failed to store list: %w", err)
+	}
+	if w.animes != nil {
+		if err := w.animes.Save(ctx, entries); err != nil {
+			log.Printf("Warning: Cache warmer failed to save %s entries to anime_caches: %v", name, err)
+		}
+	}
+	return entries, nil
+}
+
+// Status returns the outcome of the last run
+func (w *Warmer) Status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status := w.status
+	status.Enabled = w.interval > 0
+	status.Interval = w.interval.String()
+	status.Running = w.isRunning
+	return status
+}
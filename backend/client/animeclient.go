@@ -1,9 +1,11 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,9 +17,10 @@ import (
 var _ AnimeServiceAPIClient = (*AnimeClient)(nil)
 
 type AnimeClient struct {
-	client    *resty.Client
-	baseURL   string
-	requestID string // Store requestID per instance for forwarding
+	client       *resty.Client
+	baseURL      string
+	requestID    string // Store requestID per instance for forwarding
+	includeAdult bool   // Forwarded as ?includeAdult=true, anime-service leaves adult anime out of lists otherwise
 }
 
 // NewAnimeClient creates a new client for interacting with the anime-service.
@@ -51,38 +54,52 @@ func (c *AnimeClient) WithRequestID(requestID string) AnimeServiceAPIClient {
 	return &newC // Return the concrete type, which satisfies the interface
 }
 
-// Helper to prepare a request with common settings like RequestID
-func (c *AnimeClient) R() *resty.Request {
-	req := c.client.R()
+// WithIncludeAdult returns a copy of the client whose requests let adult anime into anime-service lists
+func (c *AnimeClient) WithIncludeAdult(include bool) AnimeServiceAPIClient {
+	newC := *c
+	newC.includeAdult = include
+	return &newC
+}
+
+// Helper to prepare a request with common settings like RequestID and the adult content setting.
+// The request is cancelled with ctx, retries included.
+func (c *AnimeClient) R(ctx context.Context) *resty.Request {
+	req := c.client.R().SetContext(ctx)
 	if c.requestID != "" {
 		req.SetHeader("X-Request-ID", c.requestID)
 	}
+	if c.includeAdult {
+		req.SetQueryParam("includeAdult", "true")
+	}
 	return req
 }
 
 // GetAnimeDetailsAndProviders fetches anime details and providers from the anime-service.
 // The anime-service returns {"anime": ..., "providers": ...}
-func (c *AnimeClient) GetAnimeDetailsAndProviders(animeID int) (*models.AnimeDetails, []models.WatchProvider, error) {
+// descriptionFormat is "plain", "markdown" or "html"; empty uses the anime-service default (sanitized html).
+func (c *AnimeClient) GetAnimeDetailsAndProviders(ctx context.Context, animeID int, descriptionFormat string) (*models.AnimeDetails, []models.WatchProvider, error) {
 	var result struct {
 		Anime     *models.AnimeDetails   `json:"anime"`
 		Providers []models.WatchProvider `json:"providers"`
 	}
 
-	resp, err := c.R().
-		SetResult(&result).
-		Get(fmt.Sprintf("%s/anime/%d", c.baseURL, animeID))
+	req := c.R(ctx).SetResult(&result)
+	if descriptionFormat != "" {
+		req.SetQueryParam("descriptionFormat", descriptionFormat)
+	}
+	resp, err := req.Get(fmt.Sprintf("%s/anime/%d", c.baseURL, animeID))
 
 	if err != nil {
 		log.Printf("Error calling anime-service for details (ID: %d): %v", animeID, err)
-		return nil, nil, fmt.Errorf("anime-service call failed: %w", err)
+		return nil, nil, callError("details", err)
 	}
 
 	if !resp.IsSuccess() {
 		log.Printf("anime-service returned error for details (ID: %d) - Status: %s, Body: %s", animeID, resp.Status(), resp.String())
-		return nil, nil, fmt.Errorf("anime-service error (status %s): %s", resp.Status(), resp.String())
+		return nil, nil, statusError("details", resp)
 	}
 	if result.Anime == nil {
-		return nil, nil, fmt.Errorf("anime-service returned no anime data in expected structure for ID %d", animeID)
+		return nil, nil, fmt.Errorf("%w: anime-service returned no anime data in expected structure for ID %d", ErrUpstreamUnavailable, animeID)
 	}
 
 	return result.Anime, result.Providers, nil
@@ -90,11 +107,47 @@ func (c *AnimeClient) GetAnimeDetailsAndProviders(animeID int) (*models.AnimeDet
 
 // GetAnimeByID is used by user_animelist_controller. It should get details from anime-service.
 // It calls the same endpoint as GetAnimeDetailsAndProviders but extracts only AnimeDetails.
-func (c *AnimeClient) GetAnimeByID(animeID int) (*models.AnimeDetails, error) {
-	animeDetails, _, err := c.GetAnimeDetailsAndProviders(animeID)
+func (c *AnimeClient) GetAnimeByID(ctx context.Context, animeID int) (*models.AnimeDetails, error) {
+	animeDetails, _, err := c.GetAnimeDetailsAndProviders(ctx, animeID, "")
 	return animeDetails, err
 }
 
+// maxBatchIDs is the largest ID list anime-service's /anime/batch accepts
+const maxBatchIDs = 500
+
+// GetAnimesByIDs fetches list entries for many anime in as few calls as possible.
+// Results follow the order of animeIDs, IDs unknown to anime-service are left out.
+func (c *AnimeClient) GetAnimesByIDs(ctx context.Context, animeIDs []int) ([]models.AnimeCache, error) {
+	animes := make([]models.AnimeCache, 0, len(animeIDs))
+	for start := 0; start < len(animeIDs); start += maxBatchIDs {
+		end := start + maxBatchIDs
+		if end > len(animeIDs) {
+			end = len(animeIDs)
+		}
+		ids := make([]string, 0, end-start)
+		for _, id := range animeIDs[start:end] {
+			ids = append(ids, strconv.Itoa(id))
+		}
+
+		var result struct {
+			Data []models.AnimeCache `json:"data"`
+		}
+		resp, err := c.R(ctx).
+			SetQueryParam("ids", strings.Join(ids, ",")).
+			SetResult(&result).
+			Get(fmt.Sprintf("%s/anime/batch", c.baseURL))
+
+		if err != nil {
+			return nil, callError("batch", err)
+		}
+		if !resp.IsSuccess() {
+			return nil, statusError("batch", resp)
+		}
+		animes = append(animes, result.Data...)
+	}
+	return animes, nil
+}
+
 // Helper for paged results from anime-service that use {"data": ..., "meta": ...} structure
 type pagedAnimeCacheResult struct {
 	Data []models.AnimeCache `json:"data"`
@@ -107,12 +160,14 @@ type pagedAnimeCacheResult struct {
 	} `json:"meta"`
 }
 
-// SearchAnime searches for anime through the anime-service
-func (c *AnimeClient) SearchAnime(query string, page, perPage int) ([]models.AnimeCache, int, error) {
+// SearchAnime searches for anime through the anime-service.
+// local searches the titles anime-service has stored instead of AniList (typo tolerant).
+func (c *AnimeClient) SearchAnime(ctx context.Context, query string, local bool, page, perPage int) ([]models.AnimeCache, int, error) {
 	var result pagedAnimeCacheResult
-	resp, err := c.R().
+	resp, err := c.R(ctx).
 		SetQueryParams(map[string]string{
 			"q":       query,
+			"local":   strconv.FormatBool(local),
 			"page":    fmt.Sprintf("%d", page),
 			"perPage": fmt.Sprintf("%d", perPage),
 		}).
@@ -120,79 +175,106 @@ func (c *AnimeClient) SearchAnime(query string, page, perPage int) ([]models.Ani
 		Get(fmt.Sprintf("%s/anime/search", c.baseURL))
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for search: %w", err)
+		return nil, 0, callError("search", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on search (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("search", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
 
 // GetPopularAnime from anime-service
-func (c *AnimeClient) GetPopularAnime(page, perPage int) ([]models.AnimeCache, int, error) {
+func (c *AnimeClient) GetPopularAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error) {
 	var result pagedAnimeCacheResult
-	resp, err := c.R().
+	resp, err := c.R(ctx).
 		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
 		SetResult(&result).
 		Get(fmt.Sprintf("%s/anime/popular", c.baseURL))
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for popular: %w", err)
+		return nil, 0, callError("popular", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on popular (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("popular", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
 
 // GetTrendingAnime from anime-service
-func (c *AnimeClient) GetTrendingAnime(page, perPage int) ([]models.AnimeCache, int, error) {
+func (c *AnimeClient) GetTrendingAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error) {
 	var result pagedAnimeCacheResult
-	resp, err := c.R().
+	resp, err := c.R(ctx).
 		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
 		SetResult(&result).
 		Get(fmt.Sprintf("%s/anime/trending", c.baseURL))
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for trending: %w", err)
+		return nil, 0, callError("trending", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on trending (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("trending", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
 
 // GetAnimeBySeason from anime-service
 // Note: anime-service takes /:year/:season in path, client was sending as query params. Correcting.
-func (c *AnimeClient) GetAnimeBySeason(year int, season string, page, perPage int) ([]models.AnimeCache, int, error) {
+func (c *AnimeClient) GetAnimeBySeason(ctx context.Context, year int, season string, page, perPage int) ([]models.AnimeCache, int, error) {
 	var result pagedAnimeCacheResult
-	resp, err := c.R().
+	resp, err := c.R(ctx).
 		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
 		SetResult(&result).
 		Get(fmt.Sprintf("%s/anime/season/%d/%s", c.baseURL, year, season))
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for season: %w", err)
+		return nil, 0, callError("season", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on season (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("season", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
 
-// GetAnimeRecommendations (placeholder, adapt if anime-service implements it properly)
-// The anime-service's placeholder doesn't use userID.
-func (c *AnimeClient) GetAnimeRecommendations(page, perPage int) ([]models.AnimeCache, int, error) {
-	var result pagedAnimeCacheResult
-	resp, err := c.R().
-		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
+// pagedRecommendationResult is the paged response of anime-service's recommendation endpoints
+type pagedRecommendationResult struct {
+	Data []models.Recommendation `json:"data"`
+	Meta struct {
+		Total int `json:"total"`
+	} `json:"meta"`
+}
+
+// GetAnimeRecommendations fetches the anime recommended to fans of several seed anime, ranked by anime-service
+func (c *AnimeClient) GetAnimeRecommendations(ctx context.Context, seedIDs []int, page, perPage int) ([]models.Recommendation, int, error) {
+	ids := make([]string, len(seedIDs))
+	for i, id := range seedIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	var result pagedRecommendationResult
+	resp, err := c.R(ctx).
+		SetQueryParams(map[string]string{"ids": strings.Join(ids, ","), "page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
 		SetResult(&result).
 		Get(fmt.Sprintf("%s/anime/recommendations", c.baseURL))
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for recommendations: %w", err)
+		return nil, 0, callError("recommendations", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on recommendations (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("recommendations", resp)
+	}
+	return result.Data, result.Meta.Total, nil
+}
+
+// GetRecommendationsForAnime fetches a page of the anime recommended to fans of one anime, best rated first
+func (c *AnimeClient) GetRecommendationsForAnime(ctx context.Context, animeID int, page, perPage int) ([]models.Recommendation, int, error) {
+	var result pagedRecommendationResult
+	resp, err := c.R(ctx).
+		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/anime/%d/recommendations", c.baseURL, animeID))
+	if err != nil {
+		return nil, 0, callError("anime recommendations", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, 0, statusError("anime recommendations", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
@@ -200,73 +282,247 @@ func (c *AnimeClient) GetAnimeRecommendations(page, perPage int) ([]models.Anime
 // AddWatchProvider - This function might be for admin purposes.
 // The user-service typically wouldn't directly tell anime-service to add a generic provider
 // unless it's a "suggestion" feature. The endpoint in anime-service is POST /api/v1/anime/:animeId/providers
-func (c *AnimeClient) AddWatchProviderToAnime(animeID int, providerData models.WatchProvider) (*models.WatchProvider, error) {
+func (c *AnimeClient) AddWatchProviderToAnime(ctx context.Context, animeID int, providerData models.WatchProvider) (*models.WatchProvider, error) {
 	var result models.WatchProvider // Assuming anime-service returns the created provider
-	resp, err := c.R().
+	resp, err := c.R(ctx).
 		SetBody(providerData).
 		SetResult(&result).
 		Post(fmt.Sprintf("%s/anime/%d/providers", c.baseURL, animeID))
 
 	if err != nil {
-		return nil, fmt.Errorf("anime-service call failed for adding provider: %w", err)
+		return nil, callError("adding provider", err)
 	}
 	if resp.StatusCode() != 201 { // Assuming 201 Created
-		return nil, fmt.Errorf("anime-service error adding provider (status %s): %s", resp.Status(), resp.String())
+		return nil, statusError("adding provider", resp)
 	}
 	return &result, nil
 }
 
-// ExploreAnime calls the anime-service's explore endpoint
-func (c *AnimeClient) ExploreAnime(tags []string, page, perPage int) ([]models.AnimeCache, int, error) {
+// ExploreAnime calls the anime-service's explore endpoint.
+// Anime having any of excludeTags are left out; a nil minTagRank uses the anime-service default.
+func (c *AnimeClient) ExploreAnime(ctx context.Context, tags []string, excludeTags []string, minTagRank *int, page, perPage int) ([]models.AnimeCache, int, error) {
 	var result pagedAnimeCacheResult
-	resp, err := c.R().
+	req := c.R(ctx).
 		SetQueryParams(map[string]string{
 			"tags":    strings.Join(tags, ","), // anime-service expects comma-separated
 			"page":    fmt.Sprintf("%d", page),
 			"perPage": fmt.Sprintf("%d", perPage),
 		}).
-		SetResult(&result).
-		Get(fmt.Sprintf("%s/anime/explore", c.baseURL))
+		SetResult(&result)
+	if len(excludeTags) > 0 {
+		req.SetQueryParam("excludeTags", strings.Join(excludeTags, ","))
+	}
+	if minTagRank != nil {
+		req.SetQueryParam("minTagRank", fmt.Sprintf("%d", *minTagRank))
+	}
+	resp, err := req.Get(fmt.Sprintf("%s/anime/explore", c.baseURL))
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for explore: %w", err)
+		return nil, 0, callError("explore", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on explore (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("explore", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
 
 // GetUpcomingAnime from anime-service
-func (c *AnimeClient) GetUpcomingAnime(page, perPage int) ([]models.AnimeCache, int, error) {
+func (c *AnimeClient) GetUpcomingAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error) {
 	var result pagedAnimeCacheResult // Assuming pagedAnimeCacheResult is defined as before
-	resp, err := c.R().
+	resp, err := c.R(ctx).
 		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
 		SetResult(&result).
 		Get(fmt.Sprintf("%s/anime/upcoming", c.baseURL)) // Ensure this path matches anime-service
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for upcoming: %w", err)
+		return nil, 0, callError("upcoming", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on upcoming (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("upcoming", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
 
 // GetRecentlyReleasedAnime from anime-service
-func (c *AnimeClient) GetRecentlyReleasedAnime(page, perPage int) ([]models.AnimeCache, int, error) {
+func (c *AnimeClient) GetRecentlyReleasedAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error) {
 	var result pagedAnimeCacheResult // Assuming pagedAnimeCacheResult is defined
-	resp, err := c.R().
+	resp, err := c.R(ctx).
 		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
 		SetResult(&result).
 		Get(fmt.Sprintf("%s/anime/recently-released", c.baseURL)) // Ensure this path matches anime-service
 
 	if err != nil {
-		return nil, 0, fmt.Errorf("anime-service call failed for recently-released: %w", err)
+		return nil, 0, callError("recently-released", err)
 	}
 	if !resp.IsSuccess() {
-		return nil, 0, fmt.Errorf("anime-service error on recently-released (status %s): %s", resp.Status(), resp.String())
+		return nil, 0, statusError("recently-released", resp)
 	}
 	return result.Data, result.Meta.Total, nil
 }
+
+// GetAnimeCharacters fetches a page of an anime's characters and voice actors from anime-service
+func (c *AnimeClient) GetAnimeCharacters(ctx context.Context, animeID int, language string, page, perPage int) ([]models.CharacterEdge, int, error) {
+	var result struct {
+		Data []models.CharacterEdge `json:"data"`
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	params := map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}
+	if language != "" {
+		params["language"] = language
+	}
+	resp, err := c.R(ctx).
+		SetQueryParams(params).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/anime/%d/characters", c.baseURL, animeID))
+
+	if err != nil {
+		return nil, 0, callError("characters", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, 0, statusError("characters", resp)
+	}
+	return result.Data, result.Meta.Total, nil
+}
+
+// GetStudio fetches a studio profile and a page of its anime from anime-service
+func (c *AnimeClient) GetStudio(ctx context.Context, studioID int, page, perPage int) (*models.Studio, []models.AnimeWork, int, error) {
+	var result struct {
+		Studio *models.Studio     `json:"studio"`
+		Data   []models.AnimeWork `json:"data"`
+		Meta   struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	resp, err := c.R(ctx).
+		SetQueryParams(map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/studios/%d", c.baseURL, studioID))
+
+	if err != nil {
+		return nil, nil, 0, callError("studio", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, nil, 0, statusError("studio", resp)
+	}
+	return result.Studio, result.Data, result.Meta.Total, nil
+}
+
+// GetStaff fetches a staff profile and a page of their anime from anime-service.
+// works is "staff" (production credits) or "voice" (voice acting roles).
+func (c *AnimeClient) GetStaff(ctx context.Context, staffID int, works string, page, perPage int) (*models.Staff, []models.AnimeWork, int, error) {
+	var result struct {
+		Staff *models.Staff      `json:"staff"`
+		Data  []models.AnimeWork `json:"data"`
+		Meta  struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	params := map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}
+	if works != "" {
+		params["works"] = works
+	}
+	resp, err := c.R(ctx).
+		SetQueryParams(params).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/staff/%d", c.baseURL, staffID))
+
+	if err != nil {
+		return nil, nil, 0, callError("staff", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, nil, 0, statusError("staff", resp)
+	}
+	return result.Staff, result.Data, result.Meta.Total, nil
+}
+
+// GetAiringSchedule fetches a page of episodes airing between from and to from anime-service.
+// from and to are forwarded as given (Unix seconds or RFC 3339), empty values use anime-service's defaults.
+func (c *AnimeClient) GetAiringSchedule(ctx context.Context, from, to string, page, perPage int) ([]models.AiringScheduleEntry, int, error) {
+	var result struct {
+		Data []models.AiringScheduleEntry `json:"data"`
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	params := map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}
+	if from != "" {
+		params["from"] = from
+	}
+	if to != "" {
+		params["to"] = to
+	}
+	resp, err := c.R(ctx).
+		SetQueryParams(params).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/anime/airing", c.baseURL))
+
+	if err != nil {
+		return nil, 0, callError("airing schedule", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, 0, statusError("airing schedule", resp)
+	}
+	return result.Data, result.Meta.Total, nil
+}
+
+// BrowseAnime fetches a page of anime matching the given /anime/browse filters (e.g. "genres", "yearFrom", "sort")
+func (c *AnimeClient) BrowseAnime(ctx context.Context, filters map[string]string, page, perPage int) ([]models.AnimeCache, int, error) {
+	var result pagedAnimeCacheResult
+	params := map[string]string{"page": fmt.Sprintf("%d", page), "perPage": fmt.Sprintf("%d", perPage)}
+	for key, value := range filters {
+		params[key] = value
+	}
+	resp, err := c.R(ctx).
+		SetQueryParams(params).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/anime/browse", c.baseURL))
+
+	if err != nil {
+		return nil, 0, callError("browse", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, 0, statusError("browse", resp)
+	}
+	return result.Data, result.Meta.Total, nil
+}
+
+// GetGenres fetches the genre catalog from anime-service
+func (c *AnimeClient) GetGenres(ctx context.Context) ([]string, error) {
+	var result struct {
+		Data []string `json:"data"`
+	}
+	resp, err := c.R(ctx).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/anime/genres", c.baseURL))
+
+	if err != nil {
+		return nil, callError("genres", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, statusError("genres", resp)
+	}
+	return result.Data, nil
+}
+
+// GetTags fetches the tag catalog and its categories from anime-service.
+// filters are forwarded as query parameters ("category", "spoilers", "adult").
+func (c *AnimeClient) GetTags(ctx context.Context, filters map[string]string) ([]models.MediaTag, []string, error) {
+	var result struct {
+		Data       []models.MediaTag `json:"data"`
+		Categories []string          `json:"categories"`
+	}
+	resp, err := c.R(ctx).
+		SetQueryParams(filters).
+		SetResult(&result).
+		Get(fmt.Sprintf("%s/anime/tags", c.baseURL))
+
+	if err != nil {
+		return nil, nil, callError("tags", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, nil, statusError("tags", resp)
+	}
+	return result.Data, result.Categories, nil
+}
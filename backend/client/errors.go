@@ -0,0 +1,65 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Error kinds returned by AnimeClient. Callers check them with errors.Is,
+// messages are for logs only and may change.
+var (
+	// ErrNotFound means anime-service doesn't know the requested resource
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited means anime-service (or AniList behind it) is rejecting requests for now
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstreamUnavailable means anime-service couldn't be reached or failed to answer
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrInvalidInput means anime-service rejected the request parameters
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// ServiceError describes an error response from anime-service.
+// It unwraps to the error kind matching its status code.
+type ServiceError struct {
+	Op         string
+	StatusCode int
+	RetryAfter string // Retry-After header of rate limited responses, as sent
+	Body       string
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("anime-service error on %s (status %d): %s", e.Op, e.StatusCode, e.Body)
+}
+
+// Unwrap maps the status code to an error kind
+func (e *ServiceError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrInvalidInput
+	default:
+		return ErrUpstreamUnavailable
+	}
+}
+
+// statusError builds the error for an unsuccessful anime-service response
+func statusError(op string, resp *resty.Response) error {
+	return &ServiceError{
+		Op:         op,
+		StatusCode: resp.StatusCode(),
+		RetryAfter: resp.Header().Get("Retry-After"),
+		Body:       resp.String(),
+	}
+}
+
+// callError marks a failed call (connection, timeout...) as ErrUpstreamUnavailable while keeping
+// the cause, so context errors still match errors.Is
+func callError(op string, err error) error {
+	return fmt.Errorf("%w: anime-service call failed for %s: %w", ErrUpstreamUnavailable, op, err)
+}
@@ -1,18 +1,32 @@
 // backend/client/interface.go
 package client
 
-import "github.com/vrstep/wawatch-backend/models"
+import (
+	"context"
+
+	"github.com/vrstep/wawatch-backend/models"
+)
 
 type AnimeServiceAPIClient interface {
 	WithRequestID(requestID string) AnimeServiceAPIClient
-	GetAnimeDetailsAndProviders(animeID int) (*models.AnimeDetails, []models.WatchProvider, error)
-	GetAnimeByID(animeID int) (*models.AnimeDetails, error)
-	SearchAnime(query string, page, perPage int) ([]models.AnimeCache, int, error)
-	GetPopularAnime(page, perPage int) ([]models.AnimeCache, int, error)
-	GetTrendingAnime(page, perPage int) ([]models.AnimeCache, int, error)
-	GetAnimeBySeason(year int, season string, page, perPage int) ([]models.AnimeCache, int, error)
-	GetAnimeRecommendations(page, perPage int) ([]models.AnimeCache, int, error)
-	ExploreAnime(tags []string, page, perPage int) ([]models.AnimeCache, int, error) // The missing one
-	GetUpcomingAnime(page, perPage int) ([]models.AnimeCache, int, error)
-	GetRecentlyReleasedAnime(page, perPage int) ([]models.AnimeCache, int, error)
+	WithIncludeAdult(include bool) AnimeServiceAPIClient
+	GetAnimeDetailsAndProviders(ctx context.Context, animeID int, descriptionFormat string) (*models.AnimeDetails, []models.WatchProvider, error)
+	GetAnimeByID(ctx context.Context, animeID int) (*models.AnimeDetails, error)
+	GetAnimesByIDs(ctx context.Context, animeIDs []int) ([]models.AnimeCache, error)
+	SearchAnime(ctx context.Context, query string, local bool, page, perPage int) ([]models.AnimeCache, int, error)
+	GetPopularAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error)
+	GetTrendingAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error)
+	GetAnimeBySeason(ctx context.Context, year int, season string, page, perPage int) ([]models.AnimeCache, int, error)
+	GetAnimeRecommendations(ctx context.Context, seedIDs []int, page, perPage int) ([]models.Recommendation, int, error)
+	GetRecommendationsForAnime(ctx context.Context, animeID int, page, perPage int) ([]models.Recommendation, int, error)
+	ExploreAnime(ctx context.Context, tags []string, excludeTags []string, minTagRank *int, page, perPage int) ([]models.AnimeCache, int, error) // The missing one
+	GetUpcomingAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error)
+	GetRecentlyReleasedAnime(ctx context.Context, page, perPage int) ([]models.AnimeCache, int, error)
+	GetAnimeCharacters(ctx context.Context, animeID int, language string, page, perPage int) ([]models.CharacterEdge, int, error)
+	GetStudio(ctx context.Context, studioID int, page, perPage int) (*models.Studio, []models.AnimeWork, int, error)
+	GetStaff(ctx context.Context, staffID int, works string, page, perPage int) (*models.Staff, []models.AnimeWork, int, error)
+	BrowseAnime(ctx context.Context, filters map[string]string, page, perPage int) ([]models.AnimeCache, int, error)
+	GetGenres(ctx context.Context) ([]string, error)
+	GetTags(ctx context.Context, filters map[string]string) ([]models.MediaTag, []string, error)
+	GetAiringSchedule(ctx context.Context, from, to string, page, perPage int) ([]models.AiringScheduleEntry, int, error)
 }
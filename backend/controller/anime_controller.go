@@ -28,21 +28,29 @@ func SetAnimeServiceClientForTest(mockClient client.AnimeServiceAPIClient) {
 	animeServiceClient = mockClient
 }
 
-// getClientWithRequestID should also work with the interface
+// getClientWithRequestID should also work with the interface.
+// Besides the request ID, it forwards the authenticated user's adult content setting:
+// anime-service leaves adult anime out of discovery and search unless the user opted in.
 func getClientWithRequestID(c *gin.Context) client.AnimeServiceAPIClient {
 	if animeServiceClient == nil {
 		InitAnimeServiceClient()
 	}
+	animeClient := animeServiceClient
 	reqID, exists := c.Get("RequestID")
 	if exists {
 		if idStr, ok := reqID.(string); ok && idStr != "" {
-			return animeServiceClient.WithRequestID(idStr)
+			animeClient = animeClient.WithRequestID(idStr)
 		}
 	}
-	return animeServiceClient
+	if userInterface, ok := c.Get("user"); ok {
+		if user, ok := userInterface.(models.User); ok && user.IncludeAdult {
+			animeClient = animeClient.WithIncludeAdult(true)
+		}
+	}
+	return animeClient
 }
 
-// SearchAnime forwards search to anime-service
+// SearchAnime forwards search to anime-service, ?local=true included
 func SearchAnime(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
@@ -53,15 +61,15 @@ func SearchAnime(c *gin.Context) {
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 
 	client := getClientWithRequestID(c)
-	results, total, err := client.SearchAnime(query, page, perPage)
+	results, total, err := client.SearchAnime(c.Request.Context(), query, c.Query("local") == "true", page, perPage)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to search anime via anime-service: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to search anime via anime-service")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
 }
 
-// GetAnimeDetails forwards request to anime-service
+// GetAnimeDetails forwards request to anime-service, including ?descriptionFormat= (plain, markdown or html)
 func GetAnimeDetails(c *gin.Context) {
 	idParam := c.Param("id")
 	animeID, err := strconv.Atoi(idParam)
@@ -71,13 +79,9 @@ func GetAnimeDetails(c *gin.Context) {
 	}
 
 	client := getClientWithRequestID(c)
-	animeDetailsFromService, providers, err := client.GetAnimeDetailsAndProviders(animeID)
+	animeDetailsFromService, providers, err := client.GetAnimeDetailsAndProviders(c.Request.Context(), animeID, c.Query("descriptionFormat"))
 	if err != nil {
-		if strings.Contains(err.Error(), "status 404") || strings.Contains(err.Error(), "no anime data") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Anime not found via anime-service: " + err.Error()})
-		} else {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to fetch anime details via anime-service: " + err.Error()})
-		}
+		respondError(c, err, "Anime not found via anime-service", "Failed to fetch anime details via anime-service")
 		return
 	}
 
@@ -135,9 +139,9 @@ func GetPopularAnime(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 	client := getClientWithRequestID(c)
-	results, total, err := client.GetPopularAnime(page, perPage)
+	results, total, err := client.GetPopularAnime(c.Request.Context(), page, perPage)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to fetch popular anime: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to fetch popular anime")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
@@ -148,9 +152,9 @@ func GetTrendingAnime(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 	client := getClientWithRequestID(c)
-	results, total, err := client.GetTrendingAnime(page, perPage)
+	results, total, err := client.GetTrendingAnime(c.Request.Context(), page, perPage)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to fetch trending anime: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to fetch trending anime")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
@@ -174,24 +178,78 @@ func GetAnimeBySeason(c *gin.Context) {
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 
 	client := getClientWithRequestID(c)
-	results, total, err := client.GetAnimeBySeason(year, seasonParam, page, perPage)
+	results, total, err := client.GetAnimeBySeason(c.Request.Context(), year, seasonParam, page, perPage)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to fetch anime by season: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to fetch anime by season")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
 }
 
-// GetAnimeRecommendations forwards to anime-service
+// MaxRecommendationSeeds is how many anime may seed recommendations, anime-service's limit
+const MaxRecommendationSeeds = 20
+
+// GetAnimeRecommendations forwards to anime-service. Recommendations are seeded with ?ids=1,2,3 when given,
+// or else with the anime the user rated best. Users who haven't watched anything yet get popular anime.
 func GetAnimeRecommendations(c *gin.Context) {
-	// Assuming recommendations in anime-service don't currently depend on user ID
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "10"))
 
+	seeds, err := parseIDList(c.Query("ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a comma-separated list of integers"})
+		return
+	}
+	if len(seeds) > MaxRecommendationSeeds {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many ids, maximum is " + strconv.Itoa(MaxRecommendationSeeds)})
+		return
+	}
+	if len(seeds) == 0 {
+		currentUser := c.MustGet("user").(models.User)
+		err := config.DB.Model(&models.UserAnimeList{}).
+			Where("user_id = ? AND status IN ?", currentUser.ID, []string{models.Completed, models.Watching, models.Rewatching}).
+			Order("score DESC NULLS LAST").Order("updated_at DESC").
+			Limit(MaxRecommendationSeeds).
+			Pluck("anime_external_id", &seeds).Error
+		if err != nil {
+			log.Printf("Error fetching recommendation seeds for user %d: %v", currentUser.ID, err)
+		}
+	}
+
 	client := getClientWithRequestID(c)
-	results, total, err := client.GetAnimeRecommendations(page, perPage) // Client method updated
+	var results []models.Recommendation
+	var total int
+	if len(seeds) == 0 {
+		var popular []models.AnimeCache
+		popular, total, err = client.GetPopularAnime(c.Request.Context(), page, perPage)
+		results = make([]models.Recommendation, len(popular))
+		for i, anime := range popular {
+			results[i] = models.Recommendation{Anime: anime}
+		}
+	} else {
+		results, total, err = client.GetAnimeRecommendations(c.Request.Context(), seeds, page, perPage)
+	}
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to fetch recommendations: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to fetch recommendations")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+}
+
+// GetRecommendationsForAnime forwards to anime-service
+func GetRecommendationsForAnime(c *gin.Context) {
+	animeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "10"))
+
+	client := getClientWithRequestID(c)
+	results, total, err := client.GetRecommendationsForAnime(c.Request.Context(), animeID, page, perPage)
+	if err != nil {
+		respondError(c, err, "Anime not found via anime-service", "Failed to fetch recommendations")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
@@ -208,13 +266,28 @@ func ExploreAnime(c *gin.Context) {
 	for i, tag := range tags {
 		tags[i] = strings.TrimSpace(tag)
 	}
+	var excludeTags []string
+	for _, tag := range strings.Split(c.Query("excludeTags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			excludeTags = append(excludeTags, tag)
+		}
+	}
+	var minTagRank *int
+	if raw := c.Query("minTagRank"); raw != "" {
+		rank, err := strconv.Atoi(raw)
+		if err != nil || rank < 0 || rank > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "minTagRank must be an integer between 0 and 100"})
+			return
+		}
+		minTagRank = &rank
+	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 
 	client := getClientWithRequestID(c)
-	results, total, err := client.ExploreAnime(tags, page, perPage)
+	results, total, err := client.ExploreAnime(c.Request.Context(), tags, excludeTags, minTagRank, page, perPage)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to explore anime: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to explore anime")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
@@ -226,9 +299,9 @@ func GetUpcomingAnime(c *gin.Context) {
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 
 	client := getClientWithRequestID(c)
-	results, total, err := client.GetUpcomingAnime(page, perPage)
+	results, total, err := client.GetUpcomingAnime(c.Request.Context(), page, perPage)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to fetch upcoming anime: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to fetch upcoming anime")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
@@ -240,10 +313,131 @@ func GetRecentlyReleasedAnime(c *gin.Context) {
 	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
 
 	client := getClientWithRequestID(c)
-	results, total, err := client.GetRecentlyReleasedAnime(page, perPage)
+	results, total, err := client.GetRecentlyReleasedAnime(c.Request.Context(), page, perPage)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch recently released anime")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+}
+
+// GetAnimeCharacters forwards to anime-service
+func GetAnimeCharacters(c *gin.Context) {
+	animeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anime ID"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "25"))
+
+	client := getClientWithRequestID(c)
+	results, total, err := client.GetAnimeCharacters(c.Request.Context(), animeID, c.Query("language"), page, perPage)
+	if err != nil {
+		respondError(c, err, "Anime not found via anime-service", "Failed to fetch characters")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+}
+
+// GetAiringSchedule forwards to anime-service
+func GetAiringSchedule(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "50"))
+
+	client := getClientWithRequestID(c)
+	results, total, err := client.GetAiringSchedule(c.Request.Context(), c.Query("from"), c.Query("to"), page, perPage)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch airing schedule")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+}
+
+// browseFilterParams are the query parameters forwarded to anime-service's /anime/browse
+var browseFilterParams = []string{
+	"q", "genres", "excludeGenres", "tags", "excludeTags", "minTagRank", "formats", "status", "season",
+	"year", "yearFrom", "yearTo", "scoreMin", "scoreMax", "episodesMin", "episodesMax", "sort",
+}
+
+// BrowseAnime forwards to anime-service
+func BrowseAnime(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+	filters := make(map[string]string)
+	for _, key := range browseFilterParams {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+
+	client := getClientWithRequestID(c)
+	results, total, err := client.BrowseAnime(c.Request.Context(), filters, page, perPage)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to fetch recently released anime: " + err.Error()})
+		respondError(c, err, "Not found", "Failed to browse anime")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": results, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
 }
+
+// GetGenres forwards to anime-service
+func GetGenres(c *gin.Context) {
+	client := getClientWithRequestID(c)
+	genres, err := client.GetGenres(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch genres")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": genres})
+}
+
+// GetTags forwards to anime-service
+func GetTags(c *gin.Context) {
+	filters := make(map[string]string)
+	for _, key := range []string{"category", "spoilers", "adult"} {
+		if value := c.Query(key); value != "" {
+			filters[key] = value
+		}
+	}
+
+	client := getClientWithRequestID(c)
+	tags, categories, err := client.GetTags(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch tags")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tags, "categories": categories})
+}
+
+// GetAnimeBatch forwards to anime-service
+func GetAnimeBatch(c *gin.Context) {
+	ids, err := parseIDList(c.Query("ids"))
+	if err != nil || len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a comma-separated list of integers"})
+		return
+	}
+
+	client := getClientWithRequestID(c)
+	results, err := client.GetAnimesByIDs(c.Request.Context(), ids)
+	if err != nil {
+		respondError(c, err, "Not found", "Failed to fetch anime batch")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+// parseIDList parses a comma-separated list of IDs, ignoring empty items
+func parseIDList(raw string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
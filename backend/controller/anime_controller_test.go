@@ -2,6 +2,7 @@
 package controller_test // Or "package controller" if in the same package
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -31,8 +32,16 @@ func (m *MockAnimeServiceClient) WithRequestID(requestID string) client.AnimeSer
 	return m // Return self for chaining
 }
 
-func (m *MockAnimeServiceClient) SearchAnime(query string, page int, perPage int) ([]models.AnimeCache, int, error) {
-	args := m.Called(query, page, perPage)
+func (m *MockAnimeServiceClient) WithIncludeAdult(include bool) client.AnimeServiceAPIClient {
+	args := m.Called(include)
+	if ret := args.Get(0); ret != nil {
+		return ret.(client.AnimeServiceAPIClient)
+	}
+	return m
+}
+
+func (m *MockAnimeServiceClient) SearchAnime(ctx context.Context, query string, local bool, page int, perPage int) ([]models.AnimeCache, int, error) {
+	args := m.Called(query, local, page, perPage)
 	var resData []models.AnimeCache
 	if args.Get(0) != nil {
 		resData = args.Get(0).([]models.AnimeCache)
@@ -40,8 +49,8 @@ func (m *MockAnimeServiceClient) SearchAnime(query string, page int, perPage int
 	return resData, args.Int(1), args.Error(2)
 }
 
-func (m *MockAnimeServiceClient) GetAnimeDetailsAndProviders(animeID int) (*models.AnimeDetails, []models.WatchProvider, error) {
-	args := m.Called(animeID)
+func (m *MockAnimeServiceClient) GetAnimeDetailsAndProviders(ctx context.Context, animeID int, descriptionFormat string) (*models.AnimeDetails, []models.WatchProvider, error) {
+	args := m.Called(animeID, descriptionFormat)
 	var ad *models.AnimeDetails
 	var wp []models.WatchProvider
 	if args.Get(0) != nil {
@@ -53,7 +62,7 @@ func (m *MockAnimeServiceClient) GetAnimeDetailsAndProviders(animeID int) (*mode
 	return ad, wp, args.Error(2)
 }
 
-func (m *MockAnimeServiceClient) GetAnimeByID(animeID int) (*models.AnimeDetails, error) {
+func (m *MockAnimeServiceClient) GetAnimeByID(ctx context.Context, animeID int) (*models.AnimeDetails, error) {
 	args := m.Called(animeID)
 	var ad *models.AnimeDetails
 	if args.Get(0) != nil {
@@ -62,7 +71,7 @@ func (m *MockAnimeServiceClient) GetAnimeByID(animeID int) (*models.AnimeDetails
 	return ad, args.Error(1)
 }
 
-func (m *MockAnimeServiceClient) GetPopularAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
+func (m *MockAnimeServiceClient) GetPopularAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
 	args := m.Called(page, perPage)
 	var resData []models.AnimeCache
 	if args.Get(0) != nil {
@@ -71,7 +80,7 @@ func (m *MockAnimeServiceClient) GetPopularAnime(page int, perPage int) ([]model
 	return resData, args.Int(1), args.Error(2)
 }
 
-func (m *MockAnimeServiceClient) GetTrendingAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
+func (m *MockAnimeServiceClient) GetTrendingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
 	args := m.Called(page, perPage)
 	var resData []models.AnimeCache
 	if args.Get(0) != nil {
@@ -80,7 +89,7 @@ func (m *MockAnimeServiceClient) GetTrendingAnime(page int, perPage int) ([]mode
 	return resData, args.Int(1), args.Error(2)
 }
 
-func (m *MockAnimeServiceClient) GetAnimeBySeason(year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
+func (m *MockAnimeServiceClient) GetAnimeBySeason(ctx context.Context, year int, season string, page int, perPage int) ([]models.AnimeCache, int, error) {
 	args := m.Called(year, season, page, perPage)
 	var resData []models.AnimeCache
 	if args.Get(0) != nil {
@@ -89,18 +98,27 @@ func (m *MockAnimeServiceClient) GetAnimeBySeason(year int, season string, page
 	return resData, args.Int(1), args.Error(2)
 }
 
-func (m *MockAnimeServiceClient) GetAnimeRecommendations(page int, perPage int) ([]models.AnimeCache, int, error) {
-	args := m.Called(page, perPage)
-	var resData []models.AnimeCache
+func (m *MockAnimeServiceClient) GetAnimeRecommendations(ctx context.Context, seedIDs []int, page int, perPage int) ([]models.Recommendation, int, error) {
+	args := m.Called(seedIDs, page, perPage)
+	var resData []models.Recommendation
 	if args.Get(0) != nil {
-		resData = args.Get(0).([]models.AnimeCache)
+		resData = args.Get(0).([]models.Recommendation)
+	}
+	return resData, args.Int(1), args.Error(2)
+}
+
+func (m *MockAnimeServiceClient) GetRecommendationsForAnime(ctx context.Context, animeID int, page int, perPage int) ([]models.Recommendation, int, error) {
+	args := m.Called(animeID, page, perPage)
+	var resData []models.Recommendation
+	if args.Get(0) != nil {
+		resData = args.Get(0).([]models.Recommendation)
 	}
 	return resData, args.Int(1), args.Error(2)
 }
 
 // **** ADDED/COMPLETED MISSING METHODS ****
-func (m *MockAnimeServiceClient) ExploreAnime(tags []string, page int, perPage int) ([]models.AnimeCache, int, error) {
-	args := m.Called(tags, page, perPage)
+func (m *MockAnimeServiceClient) ExploreAnime(ctx context.Context, tags []string, excludeTags []string, minTagRank *int, page int, perPage int) ([]models.AnimeCache, int, error) {
+	args := m.Called(tags, excludeTags, minTagRank, page, perPage)
 	var resData []models.AnimeCache
 	if args.Get(0) != nil {
 		resData = args.Get(0).([]models.AnimeCache)
@@ -108,7 +126,7 @@ func (m *MockAnimeServiceClient) ExploreAnime(tags []string, page int, perPage i
 	return resData, args.Int(1), args.Error(2)
 }
 
-func (m *MockAnimeServiceClient) GetUpcomingAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
+func (m *MockAnimeServiceClient) GetUpcomingAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
 	args := m.Called(page, perPage)
 	var resData []models.AnimeCache
 	if args.Get(0) != nil {
@@ -117,7 +135,7 @@ func (m *MockAnimeServiceClient) GetUpcomingAnime(page int, perPage int) ([]mode
 	return resData, args.Int(1), args.Error(2)
 }
 
-func (m *MockAnimeServiceClient) GetRecentlyReleasedAnime(page int, perPage int) ([]models.AnimeCache, int, error) {
+func (m *MockAnimeServiceClient) GetRecentlyReleasedAnime(ctx context.Context, page int, perPage int) ([]models.AnimeCache, int, error) {
 	args := m.Called(page, perPage)
 	var resData []models.AnimeCache
 	if args.Get(0) != nil {
@@ -126,6 +144,90 @@ func (m *MockAnimeServiceClient) GetRecentlyReleasedAnime(page int, perPage int)
 	return resData, args.Int(1), args.Error(2)
 }
 
+func (m *MockAnimeServiceClient) GetAnimeCharacters(ctx context.Context, animeID int, language string, page int, perPage int) ([]models.CharacterEdge, int, error) {
+	args := m.Called(animeID, language, page, perPage)
+	var resData []models.CharacterEdge
+	if args.Get(0) != nil {
+		resData = args.Get(0).([]models.CharacterEdge)
+	}
+	return resData, args.Int(1), args.Error(2)
+}
+
+func (m *MockAnimeServiceClient) GetStudio(ctx context.Context, studioID int, page int, perPage int) (*models.Studio, []models.AnimeWork, int, error) {
+	args := m.Called(studioID, page, perPage)
+	var studio *models.Studio
+	var works []models.AnimeWork
+	if args.Get(0) != nil {
+		studio = args.Get(0).(*models.Studio)
+	}
+	if args.Get(1) != nil {
+		works = args.Get(1).([]models.AnimeWork)
+	}
+	return studio, works, args.Int(2), args.Error(3)
+}
+
+func (m *MockAnimeServiceClient) GetStaff(ctx context.Context, staffID int, works string, page int, perPage int) (*models.Staff, []models.AnimeWork, int, error) {
+	args := m.Called(staffID, works, page, perPage)
+	var staff *models.Staff
+	var filmography []models.AnimeWork
+	if args.Get(0) != nil {
+		staff = args.Get(0).(*models.Staff)
+	}
+	if args.Get(1) != nil {
+		filmography = args.Get(1).([]models.AnimeWork)
+	}
+	return staff, filmography, args.Int(2), args.Error(3)
+}
+
+func (m *MockAnimeServiceClient) BrowseAnime(ctx context.Context, filters map[string]string, page int, perPage int) ([]models.AnimeCache, int, error) {
+	args := m.Called(filters, page, perPage)
+	var resData []models.AnimeCache
+	if args.Get(0) != nil {
+		resData = args.Get(0).([]models.AnimeCache)
+	}
+	return resData, args.Int(1), args.Error(2)
+}
+
+func (m *MockAnimeServiceClient) GetAnimesByIDs(ctx context.Context, animeIDs []int) ([]models.AnimeCache, error) {
+	args := m.Called(animeIDs)
+	var resData []models.AnimeCache
+	if args.Get(0) != nil {
+		resData = args.Get(0).([]models.AnimeCache)
+	}
+	return resData, args.Error(1)
+}
+
+func (m *MockAnimeServiceClient) GetGenres(ctx context.Context) ([]string, error) {
+	args := m.Called()
+	var genres []string
+	if args.Get(0) != nil {
+		genres = args.Get(0).([]string)
+	}
+	return genres, args.Error(1)
+}
+
+func (m *MockAnimeServiceClient) GetTags(ctx context.Context, filters map[string]string) ([]models.MediaTag, []string, error) {
+	args := m.Called(filters)
+	var tags []models.MediaTag
+	var categories []string
+	if args.Get(0) != nil {
+		tags = args.Get(0).([]models.MediaTag)
+	}
+	if args.Get(1) != nil {
+		categories = args.Get(1).([]string)
+	}
+	return tags, categories, args.Error(2)
+}
+
+func (m *MockAnimeServiceClient) GetAiringSchedule(ctx context.Context, from string, to string, page int, perPage int) ([]models.AiringScheduleEntry, int, error) {
+	args := m.Called(from, to, page, perPage)
+	var resData []models.AiringScheduleEntry
+	if args.Get(0) != nil {
+		resData = args.Get(0).([]models.AiringScheduleEntry)
+	}
+	return resData, args.Int(1), args.Error(2)
+}
+
 func TestSearchAnimePassThrough_Success(t *testing.T) {
 	_, token := createAndLoginTestUser(config.DB, "searchuser", "password") // Create a user for auth
 
@@ -138,7 +240,7 @@ func TestSearchAnimePassThrough_Success(t *testing.T) {
 	page := 1
 	perPage := 10
 
-	mockClient.On("SearchAnime", query, 1, 20).Return(expectedCaches, expectedTotal, nil).Once()
+	mockClient.On("SearchAnime", query, false, 1, 20).Return(expectedCaches, expectedTotal, nil).Once()
 
 	rr := performAuthRequest("GET", fmt.Sprintf("/ext/anime/search?q=%s&page=%d&perPage=%d", query, page, perPage), nil, token, testRouter) // USE performAuthRequest
 
@@ -155,3 +257,85 @@ func TestSearchAnimePassThrough_Success(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestGetAnimeCharactersPassThrough_Success(t *testing.T) {
+	_, token := createAndLoginTestUser(config.DB, "castuser", "password")
+
+	mockClient := new(MockAnimeServiceClient)
+	controller.SetAnimeServiceClientForTest(mockClient)
+
+	edge := models.CharacterEdge{Role: "MAIN", VoiceActors: map[string][]models.VoiceActor{}}
+	edge.Character.ID = 40
+	edge.Character.Name.Full = "Lelouch Lamperouge"
+	mockClient.On("GetAnimeCharacters", 1575, "JAPANESE", 1, 25).Return([]models.CharacterEdge{edge}, 1, nil).Once()
+
+	rr := performAuthRequest("GET", "/ext/anime/1575/characters?language=JAPANESE", nil, token, testRouter)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var responseBody struct {
+		Data []models.CharacterEdge `json:"data"`
+		Meta map[string]interface{} `json:"meta"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &responseBody)
+	assert.Len(t, responseBody.Data, 1)
+	assert.Equal(t, "MAIN", responseBody.Data[0].Role)
+	assert.Equal(t, "Lelouch Lamperouge", responseBody.Data[0].Character.Name.Full)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetAnimeRecommendationsPassThrough_WithSeeds(t *testing.T) {
+	_, token := createAndLoginTestUser(config.DB, "recouser", "password")
+
+	mockClient := new(MockAnimeServiceClient)
+	controller.SetAnimeServiceClientForTest(mockClient)
+
+	recommended := models.Recommendation{Anime: models.AnimeCache{ID: 9253, Title: "Steins;Gate"}, Rating: 120, RecommendedBy: []int{1535, 1575}}
+	mockClient.On("GetAnimeRecommendations", []int{1535, 1575}, 1, 10).Return([]models.Recommendation{recommended}, 1, nil).Once()
+
+	rr := performAuthRequest("GET", "/ext/anime/recommendations?ids=1535,1575", nil, token, testRouter)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var responseBody struct {
+		Data []models.Recommendation `json:"data"`
+		Meta map[string]interface{}  `json:"meta"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &responseBody)
+	assert.Len(t, responseBody.Data, 1)
+	assert.Equal(t, 9253, responseBody.Data[0].Anime.ID)
+	assert.Equal(t, []int{1535, 1575}, responseBody.Data[0].RecommendedBy)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestExploreAnimePassThrough_TagFilters(t *testing.T) {
+	_, token := createAndLoginTestUser(config.DB, "exploreuser", "password")
+
+	mockClient := new(MockAnimeServiceClient)
+	controller.SetAnimeServiceClientForTest(mockClient)
+
+	minTagRank := 0
+	mockClient.On("ExploreAnime", []string{"Time Skip", "Iyashikei"}, []string{"Tragedy"}, &minTagRank, 1, 20).
+		Return([]models.AnimeCache{{ID: 21, Title: "One Piece"}}, 1, nil).Once()
+
+	rr := performAuthRequest("GET", "/ext/anime/explore?tags=Time%20Skip,Iyashikei&excludeTags=Tragedy&minTagRank=0", nil, token, testRouter)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetPopularAnimePassThrough_IncludeAdultSetting(t *testing.T) {
+	user, token := createAndLoginTestUser(config.DB, "adultuser", "password")
+	config.DB.Model(&user).Update("include_adult", true)
+
+	mockClient := new(MockAnimeServiceClient)
+	controller.SetAnimeServiceClientForTest(mockClient)
+
+	mockClient.On("WithIncludeAdult", true).Return(mockClient).Once()
+	mockClient.On("GetPopularAnime", 1, 20).Return([]models.AnimeCache{{ID: 21, Title: "One Piece"}}, 1, nil).Once()
+
+	rr := performAuthRequest("GET", "/ext/anime/popular", nil, token, testRouter)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vrstep/wawatch-backend/client"
+)
+
+// errorStatus maps an error kind from the anime-service client to an HTTP status
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, client.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, client.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, client.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, client.ErrUpstreamUnavailable), errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError writes the error response matching the kind of an anime-service client error.
+// notFoundMsg is used for ErrNotFound, failureMsg for everything but invalid input and rate limiting.
+func respondError(c *gin.Context, err error, notFoundMsg string, failureMsg string) {
+	status := errorStatus(err)
+	msg := failureMsg
+	switch status {
+	case http.StatusNotFound:
+		msg = notFoundMsg
+	case http.StatusBadRequest:
+		msg = "Invalid request"
+	case http.StatusTooManyRequests:
+		var serviceErr *client.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.RetryAfter != "" {
+			c.Header("Retry-After", serviceErr.RetryAfter)
+		}
+		msg = "Rate limited by anime-service, try again later"
+	}
+	c.JSON(status, gin.H{"error": msg + ": " + err.Error()})
+}
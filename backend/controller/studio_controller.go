@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStudio forwards to anime-service
+func GetStudio(c *gin.Context) {
+	studioID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid studio ID"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+
+	client := getClientWithRequestID(c)
+	studio, works, total, err := client.GetStudio(c.Request.Context(), studioID, page, perPage)
+	if err != nil {
+		respondError(c, err, "Studio not found via anime-service", "Failed to fetch studio")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"studio": studio, "data": works, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+}
+
+// GetStaff forwards to anime-service
+func GetStaff(c *gin.Context) {
+	staffID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff ID"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+
+	client := getClientWithRequestID(c)
+	staff, works, total, err := client.GetStaff(c.Request.Context(), staffID, c.Query("works"), page, perPage)
+	if err != nil {
+		respondError(c, err, "Staff not found via anime-service", "Failed to fetch staff")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"staff": staff, "data": works, "meta": gin.H{"total": total, "page": page, "perPage": perPage, "totalPages": (total + perPage - 1) / perPage, "hasNextPage": page*perPage < total}})
+}
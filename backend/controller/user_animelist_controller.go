@@ -50,11 +50,11 @@ func AddToAnimeList(c *gin.Context) {
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Printf("AnimeID %d not in user-service cache. Fetching from anime-service.", input.AnimeID)
-			client := getClientWithRequestID(c)                                // Get client with RequestID
-			remoteAnimeDetails, fetchErr := client.GetAnimeByID(input.AnimeID) // Uses the client
-			if fetchErr != nil || remoteAnimeDetails == nil {
+			client := getClientWithRequestID(c)                                                     // Get client with RequestID
+			remoteAnimeDetails, fetchErr := client.GetAnimeByID(c.Request.Context(), input.AnimeID) // Uses the client
+			if fetchErr != nil {
 				log.Printf("Failed to fetch anime %d from anime-service: %v", input.AnimeID, fetchErr)
-				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Anime with ID %d not found via anime-service", input.AnimeID)})
+				respondError(c, fetchErr, fmt.Sprintf("Anime with ID %d not found via anime-service", input.AnimeID), "Failed to fetch anime from anime-service")
 				return
 			}
 			// Convert AnimeDetails from anime-service to local AnimeCache model and save
@@ -346,6 +346,27 @@ func GetUserAnimeList(c *gin.Context) {
 			cacheMap[ac.ID] = ac
 		}
 
+		// Hydrate entries missing from the local cache with a single batch call to anime-service
+		var missingIDs []int
+		for _, id := range animeIDs {
+			if _, found := cacheMap[id]; !found {
+				missingIDs = append(missingIDs, id)
+			}
+		}
+		if len(missingIDs) > 0 {
+			client := getClientWithRequestID(c)
+			fetched, fetchErr := client.GetAnimesByIDs(c.Request.Context(), missingIDs)
+			if fetchErr != nil {
+				log.Printf("Failed to hydrate %d anime from anime-service: %v", len(missingIDs), fetchErr)
+			}
+			for _, ac := range fetched {
+				if createErr := config.DB.Create(&ac).Error; createErr != nil {
+					log.Printf("Failed to save anime %d to user-service cache: %v", ac.ID, createErr)
+				}
+				cacheMap[ac.ID] = ac
+			}
+		}
+
 		for _, item := range userListItems {
 			respItem := UserAnimeListResponse{UserAnimeList: item}
 			if cachedAnime, found := cacheMap[item.AnimeExternalID]; found {
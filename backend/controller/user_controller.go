@@ -168,6 +168,7 @@ func GetMyProfile(c *gin.Context) {
 		"email":           user.Email,
 		"role":            user.Role,
 		"profile_picture": user.ProfilePicture,
+		"include_adult":   user.IncludeAdult,
 		"created_at":      user.CreatedAt,
 		"updated_at":      user.UpdatedAt,
 	})
@@ -185,6 +186,7 @@ func UpdateMyProfile(c *gin.Context) {
 	var input struct {
 		Email          *string `json:"email"`
 		ProfilePicture *string `json:"profile_picture"`
+		IncludeAdult   *bool   `json:"include_adult"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -193,7 +195,7 @@ func UpdateMyProfile(c *gin.Context) {
 	}
 
 	// If no fields provided, return error
-	if input.Email == nil && input.ProfilePicture == nil {
+	if input.Email == nil && input.ProfilePicture == nil && input.IncludeAdult == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
 		return
 	}
@@ -210,6 +212,9 @@ func UpdateMyProfile(c *gin.Context) {
 	if input.ProfilePicture != nil {
 		userToUpdate.ProfilePicture = *input.ProfilePicture
 	}
+	if input.IncludeAdult != nil {
+		userToUpdate.IncludeAdult = *input.IncludeAdult
+	}
 
 	if err := config.DB.Save(&userToUpdate).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile", "details": err.Error()})
@@ -222,6 +227,7 @@ func UpdateMyProfile(c *gin.Context) {
 		"email":           userToUpdate.Email,
 		"role":            userToUpdate.Role,
 		"profile_picture": userToUpdate.ProfilePicture,
+		"include_adult":   userToUpdate.IncludeAdult,
 		"created_at":      userToUpdate.CreatedAt,
 		"updated_at":      userToUpdate.UpdatedAt,
 	})
@@ -0,0 +1,2 @@
+-- Per-user content setting: adult anime are left out of discovery and search unless the user opts in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS include_adult BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,16 @@
+package models
+
+// NextAiringEpisode is the next episode of a releasing anime to air
+type NextAiringEpisode struct {
+	Episode         int   `json:"episode"`
+	AiringAt        int64 `json:"airingAt"`        // Unix timestamp (seconds)
+	TimeUntilAiring int64 `json:"timeUntilAiring"` // Seconds, as computed by anime-service when serving
+}
+
+// AiringScheduleEntry is one episode airing in a time window
+type AiringScheduleEntry struct {
+	ID       int        `json:"id"`
+	Episode  int        `json:"episode"`
+	AiringAt int64      `json:"airingAt"` // Unix timestamp (seconds)
+	Anime    AnimeCache `json:"anime"`
+}
@@ -10,6 +10,8 @@ type AnimeCache struct {
 	CoverImage    string `json:"cover_image"`                              // URL to the cover image
 	Format        string `json:"format"`                                   // e.g., TV, MOVIE, OVA
 	TotalEpisodes *int   `json:"total_episodes"`                           // Pointer for nullable/unknown
+	// Set by anime-service when the entry came from a fallback source, in which case ID is not an AniList ID
+	MetadataSource string `json:"metadata_source,omitempty" gorm:"-"`
 	// Add other frequently accessed, relatively static fields if needed
 	// LastFetched time.Time `json:"-"` // Track when details were last fetched from API (optional)
 }
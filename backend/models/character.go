@@ -0,0 +1,36 @@
+package models
+
+// Character is an anime character as returned by AniList
+type Character struct {
+	ID   int `json:"id"`
+	Name struct {
+		Full   string `json:"full"`
+		Native string `json:"native"`
+	} `json:"name"`
+	Image struct {
+		Large  string `json:"large"`
+		Medium string `json:"medium"`
+	} `json:"image"`
+}
+
+// VoiceActor is a staff member voicing a character
+type VoiceActor struct {
+	ID   int `json:"id"`
+	Name struct {
+		Full   string `json:"full"`
+		Native string `json:"native"`
+	} `json:"name"`
+	Language string `json:"language"` // e.g. Japanese, English
+	Image    struct {
+		Large  string `json:"large"`
+		Medium string `json:"medium"`
+	} `json:"image"`
+	RoleNotes string `json:"roleNotes,omitempty"` // e.g. "child", "ep 5"
+}
+
+// CharacterEdge is a character's appearance in an anime, with its cast
+type CharacterEdge struct {
+	Role        string                  `json:"role"` // MAIN, SUPPORTING or BACKGROUND
+	Character   Character               `json:"character"`
+	VoiceActors map[string][]VoiceActor `json:"voiceActors"` // Grouped by language
+}
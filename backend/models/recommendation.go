@@ -0,0 +1,9 @@
+package models
+
+// Recommendation is an anime AniList users recommend to fans of another
+type Recommendation struct {
+	Anime  AnimeCache `json:"anime"`
+	Rating int        `json:"rating"` // Net user votes for the pairing, summed over seeds in aggregated results
+	// Seed anime that recommend it, only set in recommendations aggregated from several seeds
+	RecommendedBy []int `json:"recommendedBy,omitempty"`
+}
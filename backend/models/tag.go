@@ -0,0 +1,22 @@
+package models
+
+// MediaTag is an AniList tag from the tag catalog
+type MediaTag struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	Category         string `json:"category"`         // e.g. "Theme-Comedy", "Setting-Scene"
+	IsGeneralSpoiler bool   `json:"isGeneralSpoiler"` // The tag itself spoils whatever it is attached to
+	IsAdult          bool   `json:"isAdult"`
+}
+
+// AnimeTag is a tag attached to an anime, with how strongly it applies
+type AnimeTag struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	Category         string `json:"category"`
+	Rank             int    `json:"rank"`             // Relevance to this anime, 0-100
+	IsGeneralSpoiler bool   `json:"isGeneralSpoiler"` // The tag spoils whatever it is attached to
+	IsMediaSpoiler   bool   `json:"isMediaSpoiler"`   // The tag spoils this anime only
+	IsAdult          bool   `json:"isAdult"`
+}
@@ -9,4 +9,5 @@ type User struct {
 	Email          string `json:"email" gorm:"unique"`
 	Role           string `json:"role"`
 	ProfilePicture string `json:"profile_picture" gorm:"default:'default.jpg'"`
+	IncludeAdult   bool   `json:"include_adult" gorm:"not null;default:false"` // Show adult anime in discovery and search
 }
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/vrstep/wawatch-backend/controller"
 	"github.com/vrstep/wawatch-backend/middleware"
@@ -16,19 +18,36 @@ func AnimePassThroughRoutes(router *gin.Engine) {
 	// Let's assume paths here mirror anime-service for clarity.
 	proxiedAnime := router.Group("/ext/anime")
 	proxiedAnime.Use(middleware.RequireAuth) // Using /ext to denote external call
+	proxiedAnime.Use(middleware.Deadline(middleware.DefaultDeadline))
 	{
-		proxiedAnime.GET("/search", controller.SearchAnime)
-		proxiedAnime.GET("/popular", controller.GetPopularAnime)
-		proxiedAnime.GET("/trending", controller.GetTrendingAnime)
-		proxiedAnime.GET("/upcoming", controller.GetUpcomingAnime)                  // New in anime-service
-		proxiedAnime.GET("/recently-released", controller.GetRecentlyReleasedAnime) // New
-		proxiedAnime.GET("/explore", controller.ExploreAnime)                       // New
-		proxiedAnime.GET("/season/:year/:season", controller.GetAnimeBySeason)
+		proxiedAnime.GET("/search", middleware.HTTPCache(middleware.CacheShort), controller.SearchAnime)
+		proxiedAnime.GET("/popular", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetPopularAnime)
+		proxiedAnime.GET("/trending", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetTrendingAnime)
+		proxiedAnime.GET("/upcoming", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetUpcomingAnime)                  // New in anime-service
+		proxiedAnime.GET("/recently-released", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetRecentlyReleasedAnime) // New
+		proxiedAnime.GET("/explore", middleware.HTTPCache(middleware.CacheShort), controller.ExploreAnime)                           // New
+		proxiedAnime.GET("/season/:year/:season", middleware.HTTPCache(middleware.CacheDiscovery), controller.GetAnimeBySeason)
+		proxiedAnime.GET("/browse", middleware.HTTPCache(middleware.CacheShort), controller.BrowseAnime)
+		proxiedAnime.GET("/genres", middleware.HTTPCache(middleware.CacheCatalog), controller.GetGenres)
+		proxiedAnime.GET("/tags", middleware.HTTPCache(middleware.CacheCatalog), controller.GetTags)
+		proxiedAnime.GET("/batch", middleware.HTTPCache(middleware.CacheDetails), middleware.Deadline(2*time.Minute), controller.GetAnimeBatch) // ?ids=1,2,3, one anime-service call per 500
+		proxiedAnime.GET("/airing", middleware.HTTPCache(middleware.CacheShort), controller.GetAiringSchedule)                                  // Weekly calendar
+
+		// Recommendations are seeded with the anime the user rated best, unless ?ids= names the seeds.
+		// anime-service makes one AniList call per seed.
+		proxiedAnime.GET("/recommendations", middleware.HTTPCache(middleware.CacheDiscovery), middleware.Deadline(time.Minute), controller.GetAnimeRecommendations)
 
-		// Recommendations might be user-specific eventually, but anime-service's is generic for now.
-		// If it becomes personalized, anime-service would need user context (e.g. user ID).
-		proxiedAnime.GET("/recommendations", controller.GetAnimeRecommendations)
+		proxiedAnime.GET("/:id", middleware.HTTPCache(middleware.CacheDetails), controller.GetAnimeDetails) // Gets details & providers
+		proxiedAnime.GET("/:id/characters", middleware.HTTPCache(middleware.CacheDetails), controller.GetAnimeCharacters)
+		proxiedAnime.GET("/:id/recommendations", middleware.HTTPCache(middleware.CacheDetails), controller.GetRecommendationsForAnime)
+	}
 
-		proxiedAnime.GET("/:id", controller.GetAnimeDetails) // Gets details & providers
+	// Studio and staff profiles with filmographies, also served by anime-service
+	proxiedPeople := router.Group("/ext")
+	proxiedPeople.Use(middleware.RequireAuth)
+	proxiedPeople.Use(middleware.Deadline(middleware.DefaultDeadline))
+	{
+		proxiedPeople.GET("/studios/:id", middleware.HTTPCache(middleware.CacheDetails), controller.GetStudio)
+		proxiedPeople.GET("/staff/:id", middleware.HTTPCache(middleware.CacheDetails), controller.GetStaff)
 	}
 }